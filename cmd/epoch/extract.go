@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// runExtract converts the first (mode "first") or all (mode "all") times found in the input text, e.g. a log line.
+// Each line contains the byte offsets of the time in the text, the matching layout and the converted time.
+func runExtract(input, mode string, converter *epoch.Converter) ([]string, error) {
+	if input == "" {
+		return nil, fmt.Errorf("extract requires an input text")
+	}
+
+	parser := converter.Parser()
+
+	var matches []epoch.Match
+	switch mode {
+	case "first":
+		match, err := parser.ExtractFirst(input, converter.Location())
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	case "all":
+		matches = parser.Extract(input, converter.Location())
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%w in '%v'", epoch.ErrNoTime, input)
+		}
+	default:
+		return nil, fmt.Errorf("unknown extract mode '%v' (use first or all)", mode)
+	}
+
+	var lines []string
+	for _, match := range matches {
+		// the time of the match, parsing the text again might use another layout
+		line, err := converter.ConvertTime(match.Time)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fmt.Sprintf("%v-%v\t%v\t%v", match.Start, match.End, match.Layout, line))
+	}
+	return lines, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+func TestRunExtract(t *testing.T) {
+	type args struct {
+		input      string
+		mode       string
+		calc       string
+		formatFlag string
+		tzFlag     string
+		unitFlag   string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{name: "first", args: args{input: "[2019-01-25 21:51:38] ERROR connection lost", mode: "first", tzFlag: "UTC", unitFlag: "guess"}, want: []string{"1-20\t2006-01-02 15:04:05.999999999\t2019-01-25 21:51:38 +0000 UTC"}},
+		{name: "first/timestamp", args: args{input: "Date: Fri, 25 Jan 2019 21:51:38 +0100", mode: "first", unitFlag: "guess"}, want: []string{"6-37\tMon, 02 Jan 2006 15:04:05 -0700\t1548449498"}},
+		{name: "all/format", args: args{input: "from 2024-01-01T00:00:00Z to 25.01.2024.", mode: "all", formatFlag: "rfc3339", tzFlag: "UTC", unitFlag: "guess"}, want: []string{"5-25\t2006-01-02T15:04:05Z07:00\t2024-01-01T00:00:00Z", "29-39\t02.01.2006\t2024-01-25T00:00:00Z"}},
+		{name: "all/calc", args: args{input: "from 2024-01-01T00:00:00Z to 25.01.2024.", mode: "all", calc: "+1D", formatFlag: "rfc3339", tzFlag: "UTC", unitFlag: "guess"}, want: []string{"5-25\t2006-01-02T15:04:05Z07:00\t2024-01-02T00:00:00Z", "29-39\t02.01.2006\t2024-01-26T00:00:00Z"}},
+		{name: "no time/FAIL", args: args{input: "connection lost", mode: "all", unitFlag: "guess"}, wantErr: true},
+		{name: "ambiguous/FAIL", args: args{input: "on 03/04/2024", mode: "first", unitFlag: "guess"}, wantErr: true},
+		{name: "empty/FAIL", args: args{mode: "first", unitFlag: "guess"}, wantErr: true},
+		{name: "mode/FAIL", args: args{input: "2024-01-01", mode: "last", unitFlag: "guess"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runExtract(tt.args.input, tt.args.mode, testConverter(t, "", tt.args.unitFlag, tt.args.formatFlag, tt.args.tzFlag, tt.args.calc, epoch.Calculator{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runExtract() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("runExtract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// runInspect lists the times of a JWT, a Set-Cookie line or HTTP headers, one "name: time" per line.
+func runInspect(input string, converter *epoch.Converter) ([]string, error) {
+	if input == "" {
+		return nil, fmt.Errorf("inspect requires a JWT, a Set-Cookie line or HTTP headers")
+	}
+
+	fields, err := epoch.Inspect(input, converter.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no times found", epoch.ErrInspect)
+	}
+
+	return renderFields(fields, converter)
+}
+
+// renderFields outputs one "name: time" per field, or the note of fields without time.
+func renderFields(fields []epoch.Field, converter *epoch.Converter) ([]string, error) {
+	var lines []string
+	for _, field := range fields {
+		if field.Time.IsZero() {
+			lines = append(lines, fmt.Sprintf("%v: %v", field.Name, field.Note))
+			continue
+		}
+
+		line, err := converter.Render(field.Time.In(converter.Location()))
+		if err != nil {
+			return nil, err
+		}
+		if field.Note != "" {
+			line += ", " + field.Note
+		}
+		lines = append(lines, fmt.Sprintf("%v: %v", field.Name, line))
+	}
+	return lines, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+func TestRunInspect(t *testing.T) {
+	type args struct {
+		input      string
+		now        string
+		formatFlag string
+		tzFlag     string
+		unitFlag   string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		// {"iat":1700000000,"exp":1700003600}
+		{name: "jwt", args: args{input: "eyJhbGciOiJIUzI1NiJ9.eyJpYXQiOjE3MDAwMDAwMDAsImV4cCI6MTcwMDAwMzYwMH0.sig", tzFlag: "UTC", unitFlag: "guess"}, want: []string{"iat: 2023-11-14 22:13:20 +0000 UTC", "exp: 2023-11-14 23:13:20 +0000 UTC"}},
+		{name: "jwt/unit", args: args{input: "Bearer eyJhbGciOiJIUzI1NiJ9.eyJpYXQiOjE3MDAwMDAwMDAsImV4cCI6MTcwMDAwMzYwMH0.sig", unitFlag: "ms"}, want: []string{"iat: 1700000000000", "exp: 1700003600000"}},
+		{name: "headers", args: args{input: "HTTP/1.1 503 Service Unavailable\nDate: Wed, 21 Oct 2015 07:28:00 GMT\nRetry-After: 120\nExpires: 0", now: "2015-10-21T07:28:00Z", formatFlag: "rfc3339", tzFlag: "UTC", unitFlag: "guess"}, want: []string{"Date: 2015-10-21T07:28:00Z", "Retry-After: 2015-10-21T07:30:00Z", "Expires: invalid date '0', already expired"}},
+		{name: "cookie", args: args{input: "Set-Cookie: id=a3fWa; Max-Age=0", now: "2015-10-21T07:28:00Z", tzFlag: "UTC", unitFlag: "guess"}, want: []string{"Max-Age: 2015-10-21 07:28:00 +0000 UTC, expires immediately"}},
+		{name: "no times/FAIL", args: args{input: "Content-Type: text/html", unitFlag: "guess"}, wantErr: true},
+		{name: "empty/FAIL", args: args{unitFlag: "guess"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runInspect(tt.args.input, testConverter(t, tt.args.now, tt.args.unitFlag, tt.args.formatFlag, tt.args.tzFlag, "", epoch.Calculator{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runInspect() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("runInspect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// stringsFlag is a flag which can be given multiple times.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// defaultLayoutsFile returns the path of the layouts file used when no layouts flag is given.
+func defaultLayoutsFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "epoch", "layouts.yaml")
+}
+
+// inputLayouts returns the custom layouts for parsing the input. Input formats are either layouts
+// or names from the layouts file. Without input formats, all layouts from a given layouts file are used,
+// the layouts of the default file are only used by name, as they might match numeric timestamps.
+// The default layouts file is optional, a given layouts file has to exist.
+func inputLayouts(inputFormats []string, layoutsFile string) ([]epoch.Layout, error) {
+	if len(inputFormats) == 0 && layoutsFile == "" {
+		return nil, nil
+	}
+
+	path := layoutsFile
+	if path == "" {
+		path = defaultLayoutsFile()
+	}
+
+	var named []epoch.Layout
+	if path != "" {
+		f, err := os.Open(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && layoutsFile == "":
+		case err != nil:
+			return nil, fmt.Errorf("failed to open layouts: %w", err)
+		default:
+			defer f.Close()
+			named, err = epoch.LoadLayouts(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load layouts from %v: %w", path, err)
+			}
+		}
+	}
+
+	if len(inputFormats) == 0 {
+		return named, nil
+	}
+
+	var layouts []epoch.Layout
+	for _, format := range inputFormats {
+		layout, err := lookupLayout(format, named)
+		if err != nil {
+			return nil, err
+		}
+		layouts = append(layouts, layout)
+	}
+	return layouts, nil
+}
+
+// lookupLayout returns the named layout or parses the format as layout.
+func lookupLayout(format string, named []epoch.Layout) (epoch.Layout, error) {
+	for _, layout := range named {
+		if layout.Name == format {
+			return layout, nil
+		}
+	}
+	return epoch.ParseLayout(format)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInputLayouts(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "layouts.yaml")
+	if err := os.WriteFile(file, []byte("nginx: 02/Jan/2006:15:04:05 -0700\napp: '%Y-%m-%d %H:%M'\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		inputFormats []string
+		layoutsFile  string
+		want         []string
+		wantErr      bool
+	}{
+		{name: "all named", layoutsFile: file, want: []string{"nginx", "app"}},
+		{name: "named and custom", inputFormats: []string{"app", "%d.%m.%Y"}, layoutsFile: file, want: []string{"app", "%d.%m.%Y"}},
+		{name: "missing file/FAIL", layoutsFile: filepath.Join(t.TempDir(), "missing.yaml"), wantErr: true},
+		{name: "invalid format/FAIL", inputFormats: []string{"%Q"}, layoutsFile: file, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layouts, err := inputLayouts(tt.inputFormats, tt.layoutsFile)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("inputLayouts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var got []string
+			for _, layout := range layouts {
+				got = append(got, layout.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("inputLayouts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputLayoutsDefaultFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if defaultLayoutsFile() != filepath.Join(dir, "epoch", "layouts.yaml") {
+		t.Skip("the user configuration directory doesn't use XDG_CONFIG_HOME")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "epoch"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(defaultLayoutsFile(), []byte("compact: '%Y%m%d'\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// the layouts of the default file could match timestamps
+	layouts, err := inputLayouts(nil, "")
+	if err != nil || len(layouts) != 0 {
+		t.Fatalf("inputLayouts() = %v, %v, want no layouts", layouts, err)
+	}
+
+	layouts, err = inputLayouts([]string{"compact"}, "")
+	if err != nil || len(layouts) != 1 || layouts[0].String() != "compact" {
+		t.Fatalf("inputLayouts() = %v, %v, want compact", layouts, err)
+	}
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strconv"
@@ -22,13 +24,44 @@ var (
 
 func main() {
 	var (
-		unit        = flag.String("unit", "guess", "unit for timestamps: s, ms, us, ns")
-		format      = flag.String("format", "", "human readable output format, such as 'rfc3339' (see readme for details)")
-		tz          = flag.String("tz", "", `the timezone to use, e.g. 'Local' (default), 'UTC', or a name corresponding to the IANA Time Zone database, such as 'America/New_York'`)
-		quiet       = flag.Bool("quiet", false, "don't output guessed units")
-		versionFlag = flag.Bool("version", false, fmt.Sprintf("print version information of this release (%v)", version))
-		calc        = flag.String("calc", "", "apply basic time calculations, e.g. '+30m -5h +3M -10Y'")
+		unit          = flag.String("unit", "guess", "unit for timestamps: s, ms, us, ns")
+		format        = flag.String("format", "", "human readable output format, such as 'rfc3339' or 'relative', a strftime format such as '%Y-%m-%d %H:%M' or a pattern with a simple:, go:, java:, dotnet:, moment: or luxon: prefix (see readme for details)")
+		tz            = flag.String("tz", "", `the timezone to use, e.g. 'Local' (default), 'UTC', or a name corresponding to the IANA Time Zone database, such as 'America/New_York'`)
+		quiet         = flag.Bool("quiet", false, "don't output guessed units")
+		versionFlag   = flag.Bool("version", false, fmt.Sprintf("print version information of this release (%v)", version))
+		calc          = flag.String("calc", "", "apply basic time calculations, e.g. '+30m -5h +3M -10Y' or '/D' for the start of the day")
+		weekend       = flag.String("weekend", "sat,sun", "comma separated non-working days of the week for business day calculations, none for 7-day weeks")
+		overflow      = flag.String("overflow", "normalize", "handling of non-existent days in month and year calculations, e.g. for '2024-01-31 +1M': normalize (March 2), clamp (February 29) or error")
+		timeMode      = flag.String("time-mode", "elapsed", "how to add ns, us, ms, s, m and h across DST transitions: elapsed (physical time) or wall (wall clock time)")
+		dateMode      = flag.String("date-mode", "wall", "how to add D and W across DST transitions: wall (wall clock time) or elapsed (multiples of 24h)")
+		cron          = flag.String("cron", "", "list the next occurrences of a cron expression after the input time, e.g. '30 9 * * Mon-Fri'")
+		rrule         = flag.String("rrule", "", "list the next occurrences of an iCalendar RRULE after the input time, e.g. 'FREQ=MONTHLY;BYDAY=-1FR'")
+		count         = flag.Int("count", 5, "number of occurrences listed for the cron, rrule and oncalendar flags, or number of times listed for the step flag without an end")
+		prev          = flag.Bool("prev", false, "list previous instead of next occurrences for the cron, rrule and oncalendar flags")
+		step          = flag.String("step", "", "list times starting at the input time in steps of a calculation, e.g. '+1D' or '-15m' (see count and end flags)")
+		end           = flag.String("end", "", "exclusive end of the times listed for the step flag, as timestamp or formatted time, e.g. '2024-12-31T00:00:00Z'")
+		pairs         = flag.Bool("pairs", false, "list half-open intervals 'start<TAB>end' instead of single times for the step flag")
+		holidays      = flag.String("holidays", "", "comma separated built-in holiday sets (de, fr, uk, us) or holiday files (iCalendar or YAML list) for business day calculations")
+		relative      = flag.Bool("relative", false, "append the time relative to now to the output, e.g. '(3 hours ago)', see also the 'relative' format")
+		precision     = flag.Int("precision", 1, "maximum number of units of relative times, e.g. 2 for '2 hours 14 minutes ago'")
+		granularity   = flag.String("granularity", "s", "smallest unit of relative times: s, m, h, D, W, M or Y")
+		compact       = flag.Bool("compact", false, "abbreviate the units of relative times, e.g. '2h 14m ago'")
+		extract       = flag.String("extract", "", "find the times in the input text instead of parsing the whole input, e.g. a log line, and print their offsets and layouts: first or all")
+		dateOrder     = flag.String("date-order", "", "order of numeric dates such as '03/04/2024': mdy, dmy or ymd, ambiguous dates are rejected without it")
+		localeFlag    = flag.String("locale", "", fmt.Sprintf("language of month and weekday names for parsing the input and formatting with layouts, its common date orders are tried before the built-in layouts (%v)", strings.Join(epoch.Locales(), ", ")))
+		inspect       = flag.Bool("inspect", false, "print the times of a JWT (without verifying it), a journal entry, a Set-Cookie line or HTTP headers (Date, Expires, Last-Modified, Retry-After) with the time relative to now")
+		x509Flag      = flag.Bool("x509", false, "print the validity of PEM or DER certificates, chains, CRLs with their revocations and OCSP responses from the file arguments or stdin with the time relative to now, exits with 7 when one is expired (see expires-within)")
+		expiresWithin = flag.String("expires-within", "", "exit with 7 when a certificate, CRL or OCSP response of the x509 flag expires within the calculation from now, e.g. '+30D'")
+		stat          = flag.Bool("stat", false, "print the modification, access, change and birth times of the file arguments in nanosecond precision")
+		setTimes      = flag.String("set-times", "", "set the access and modification times of the files of the stat flag to the time, as timestamp or formatted time, e.g. '2024-01-01T00:00:00Z'")
+		metadata      = flag.Bool("metadata", false, "print the embedded times of tar (PAX) and zip archives and JPEG and TIFF images (EXIF) from the file arguments or stdin, times without timezone are interpreted in the tz flag")
+		onCalendar    = flag.String("oncalendar", "", "list the next elapse times of a systemd calendar expression after the input time, e.g. 'Mon..Fri *-*-* 09:00:00 Europe/Berlin'")
+		stream        = flag.Bool("stream", false, "convert each line of stdin until its end, lines of 'journalctl -o json' are converted by their __REALTIME_TIMESTAMP and followed by their MESSAGE")
+		nowFlag       = flag.String("now", "", "pin the current time used for empty inputs, guessing units and relative times, as formatted time or timestamp in seconds, e.g. '2024-01-01T00:00:00Z'")
+		layoutsFile   = flag.String("layouts", "", fmt.Sprintf("file with named input layouts for input-format, one 'name: layout' per line, all of them are tried when the flag is given without input-format (default %v)", defaultLayoutsFile()))
 	)
+	var inputFormats stringsFlag
+	flag.Var(&inputFormats, "input-format", "layout for parsing the input, tried before the built-in layouts and can be repeated, e.g. a Go layout, a strftime format such as '%d.%m.%Y %H:%M' (%Z only accepts UTC, GMT and the abbreviations of the tz location), a pattern with a simple:, java:, dotnet:, moment: or luxon: prefix or the name of a layout from the layouts file (see readme for details)")
 	flag.Parse()
 
 	if *versionFlag {
@@ -38,116 +71,254 @@ func main() {
 		os.Exit(0)
 	}
 
-	input, err := readInput()
-	if err != nil {
-		log.Fatalln(err)
+	// the arguments of the x509, stat and metadata flags are files, stream reads stdin line by line
+	var input string
+	if !*x509Flag && !*stat && !*metadata && !*stream {
+		var err error
+		input, err = readInput(*inspect)
+		if err != nil {
+			fatal(err)
+		}
 	}
 
-	result, err := run(input, time.Now().String(), *calc, *unit, *format, *tz, *quiet)
+	layouts, err := inputLayouts(inputFormats, *layoutsFile)
 	if err != nil {
-		log.Fatalln(err)
+		fatal(err)
 	}
 
-	fmt.Println(result)
-}
+	order, err := epoch.ParseDateOrder(*dateOrder)
+	if err != nil {
+		fatal(err)
+	}
 
-type calculation struct {
-	operator epoch.Operator
-	amount   int
-	unit     string
-}
+	calculations, err := epoch.ParseCalculations(*calc)
+	if err != nil {
+		fatal(err)
+	}
 
-func run(input string, now, calc string, unit, formatName, tz string, quiet bool) (string, error) {
-	var (
-		err          error
-		calculations []calculation
-	)
+	options := []epoch.ConverterOption{
+		epoch.WithLayouts(layouts...),
+		epoch.WithDateOrder(order),
+		epoch.WithFormat(*format),
+		epoch.WithRelative(epoch.Relative{Precision: *precision, Granularity: *granularity, Compact: *compact}),
+		epoch.WithCalculations(calculations...),
+	}
 
-	calcInpufStrings := strings.Split(calc, " ")
-	for _, calcInputString := range calcInpufStrings {
-		if strings.TrimSpace(calcInputString) == "" {
-			continue
+	loc := time.Local
+	if *tz != "" {
+		loc, err = epoch.LoadLocation(*tz)
+		if err != nil {
+			fatal(err)
 		}
-		operator, err := epoch.ToOperator(calcInputString[:1])
+		options = append(options, epoch.WithLocation(loc))
+	}
+
+	clock, err := pinClock(*nowFlag, loc)
+	if err != nil {
+		fatal(err)
+	}
+	options = append(options, epoch.WithClock(clock))
+
+	if *unit != "guess" {
+		u, err := epoch.ParseUnit(*unit)
 		if err != nil {
-			return "", err
+			fatal(err)
 		}
+		options = append(options, epoch.WithUnit(u))
+	}
 
-		amount, err := strconv.Atoi(calcInputString[1 : len(calcInputString)-1])
+	if *localeFlag != "" {
+		locale, err := epoch.LookupLocale(*localeFlag)
 		if err != nil {
-			return "", err
+			fatal(err)
 		}
+		options = append(options, epoch.WithLocale(locale))
+	}
+
+	if *stream {
+		options = append(options, epoch.WithStream())
+	}
+
+	// inspected times always show when they expire
+	if *relative || *inspect || *x509Flag {
+		options = append(options, epoch.WithAppendedRelative())
+	}
 
-		suffix := calcInputString[len(calcInputString)-1:]
-		calculations = append(calculations, calculation{operator: operator, amount: amount, unit: suffix})
+	if !*quiet {
+		options = append(options, epoch.WithNotify(func(msg string) {
+			fmt.Fprintln(os.Stderr, msg)
+		}))
 	}
 
-	if input == "" {
-		input = now
+	cal, err := calendar(*weekend, *holidays)
+	if err != nil {
+		fatal(err)
 	}
 
-	input, unit, err = parseUnit(input, unit)
+	overflowPolicy, err := epoch.ParseOverflow(*overflow)
 	if err != nil {
-		return "", err
+		fatal(err)
 	}
 
-	// If the input can be parsed as a number, we assume it's an epoch timestamp. Convert to formatted string.
-	if i, err := strconv.ParseFloat(input, 64); err == nil {
-		t := parseTimestamp(unit, int64(i), quiet).In(location(tz))
+	timeArithmetic, err := epoch.ParseMode(*timeMode)
+	if err != nil {
+		fatal(err)
+	}
 
-		if len(calculations) > 0 {
-			// when applying arithmetics here, return as timestamp again
-			for _, calc := range calculations {
-				t = epoch.Calculate(t, calc.operator, calc.amount, calc.unit)
+	dateArithmetic, err := epoch.ParseMode(*dateMode)
+	if err != nil {
+		fatal(err)
+	}
+
+	converter := epoch.NewConverter(append(options, epoch.WithCalculator(epoch.Calculator{
+		Calendar: cal,
+		Overflow: overflowPolicy,
+		TimeMode: timeArithmetic,
+		DateMode: dateArithmetic,
+		Warn: func(msg string) {
+			if !*quiet {
+				fmt.Fprintln(os.Stderr, "warning:", msg)
 			}
-			// always quite as we already output unit above in parseTimestmap
-			return strconv.FormatInt(timestamp(t, unit, true), 10), nil
+		},
+	}))...)
+
+	if *cron != "" || *rrule != "" || *onCalendar != "" {
+		occurrences, err := runSchedule(input, *cron, *rrule, *onCalendar, *count, *prev, converter)
+		if err != nil {
+			fatal(err)
+		}
+		for _, occurrence := range occurrences {
+			fmt.Println(occurrence)
 		}
+		return
+	}
 
-		format, err := epoch.FormatName(formatName)
-		return t.Format(format), err
+	if *inspect {
+		lines, err := runInspect(input, converter)
+		if err != nil {
+			fatal(err)
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		return
 	}
 
-	// Likely not an epoch timestamp as input. But a timezone and/or format was specified. Convert formatted input to another timezone and/or format.
-	if tz != "" || formatName != "" {
-		if unit != "guess" {
-			return "", fmt.Errorf("can't use unit flag together with timezone or format flag on a formatted string (omit -unit flag)")
+	if *stream {
+		if err := runStream(os.Stdin, os.Stdout, os.Stderr, converter); err != nil {
+			fatal(err)
 		}
+		return
+	}
 
-		t, _, err := epoch.ParseFormatted(input, location(tz))
+	if *metadata {
+		lines, err := runMetadata(flag.Args(), os.Stdin, converter)
 		if err != nil {
-			return "", fmt.Errorf("failed to convert input: %v", err)
+			fatal(err)
+		}
+		for _, line := range lines {
+			fmt.Println(line)
 		}
-		t = t.In(location(tz))
+		return
+	}
 
-		for _, calc := range calculations {
-			t = epoch.Calculate(t, calc.operator, calc.amount, calc.unit)
+	if *stat {
+		lines, err := runStat(flag.Args(), *setTimes, converter)
+		if err != nil {
+			fatal(err)
 		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		return
+	}
 
-		format, err := epoch.FormatName(formatName)
-		return t.Format(format), err
+	if *x509Flag {
+		lines, expiring, err := runX509(flag.Args(), os.Stdin, *expiresWithin, converter)
+		if err != nil {
+			fatal(err)
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		if len(expiring) > 0 {
+			fatal(expiringError(expiring, *expiresWithin))
+		}
+		return
 	}
 
-	// Likely not an epoch timestamp as input, output formatted input time to timestamp.
-	if formatName != "" {
-		return "", fmt.Errorf("can't use specific format when converting to timestamp (omit -format flag)")
+	if *extract != "" {
+		lines, err := runExtract(input, *extract, converter)
+		if err != nil {
+			fatal(err)
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		return
 	}
 
-	// convert formatted string to time type
-	t, _, err := epoch.ParseFormatted(input, location(tz))
+	if *step != "" {
+		// without an explicit count, the range only ends at the end flag
+		rangeCount := *count
+		if *end != "" && !isFlagSet("count") {
+			rangeCount = 0
+		}
+
+		lines, err := runRange(input, *step, *end, rangeCount, *pairs, converter)
+		if err != nil {
+			fatal(err)
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		return
+	}
+
+	result, err := converter.Convert(input)
 	if err != nil {
-		log.Fatalf("failed to convert input: %v", err)
+		fatal(err)
 	}
 
-	for _, calc := range calculations {
-		t = epoch.Calculate(t, calc.operator, calc.amount, calc.unit)
+	fmt.Println(result)
+}
+
+// Exit codes of the command by the class of the error.
+// Invalid flags exit with 2 (see the flag package).
+const (
+	exitError           = 1 // any other error
+	exitParse           = 3 // the input isn't a valid time, e.g. epoch.ErrParseFormatted or epoch.ErrNoTime
+	exitUnknownUnit     = 4 // epoch.ErrUnknownUnit
+	exitUnknownTimezone = 5 // epoch.ErrUnknownTimezone
+	exitOutOfRange      = 6 // epoch.ErrOutOfRange, e.g. a timestamp in nanoseconds after the year 2262
+	exitExpiring        = 7 // a certificate, CRL or OCSP response of the x509 flag is expired or expires within the expires-within flag
+)
+
+// exitCode returns the exit code of the error's class.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, epoch.ErrParseFormatted), errors.Is(err, epoch.ErrNoTime):
+		return exitParse
+	case errors.Is(err, epoch.ErrUnknownUnit):
+		return exitUnknownUnit
+	case errors.Is(err, epoch.ErrUnknownTimezone):
+		return exitUnknownTimezone
+	case errors.Is(err, epoch.ErrOutOfRange):
+		return exitOutOfRange
+	case errors.Is(err, errExpiring):
+		return exitExpiring
 	}
+	return exitError
+}
 
-	return strconv.FormatInt(timestamp(t, unit, quiet), 10), nil
+// fatal prints the error and exits with the code of its class.
+func fatal(err error) {
+	log.Println(err)
+	os.Exit(exitCode(err))
 }
 
-// read program input from stdin or argument
-func readInput() (string, error) {
+// read program input from stdin or argument, only the first line of stdin unless multiline
+func readInput(multiline bool) (string, error) {
 	// from stdin/pipe
 	if flag.NArg() == 0 {
 
@@ -161,7 +332,15 @@ func readInput() (string, error) {
 			return "", nil
 		}
 
-		// read the input from the pipe
+		// read the input from the pipe, e.g. HTTP headers have multiple lines
+		if multiline {
+			input, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return "", fmt.Errorf("failed to read input: %v", err)
+			}
+			return strings.TrimSpace(string(input)), nil
+		}
+
 		reader := bufio.NewReader(os.Stdin)
 		input, err := reader.ReadString('\n')
 		if err != nil {
@@ -178,86 +357,81 @@ func readInput() (string, error) {
 	return flag.Arg(0), nil
 }
 
-func parseUnit(input, unitFlag string) (string, string, error) {
-	// use suffix of input as unit, e.g.
-	// "1234567890s" -> unit: "s"; input: "1234567890"
-	//
-	// keep "s" as last element in slice, otherwise,
-	// it will match all other units as they end with an "s", too.
-	for _, unit := range []string{"ns", "us", "ms", "s"} {
-		if !strings.HasSuffix(input, unit) {
-			continue
-		}
-
-		// check if remaining input is an integer, if not
-		// it might be a time zone ending with 'unit'.
-		// (I'm currently not aware of any, but let's be sure)
-		inputTrim := strings.TrimSuffix(input, unit)
-		if _, err := strconv.ParseInt(inputTrim, 10, 64); err != nil {
-			continue
-		}
+// pinClock returns a clock fixed to the now flag, a formatted time in the location or a timestamp in seconds.
+// Without the flag, it's fixed to the current time, so all outputs of a run use the same time.
+func pinClock(nowFlag string, loc *time.Location) (epoch.Clock, error) {
+	if nowFlag == "" {
+		return epoch.FixedClock(time.Now()), nil
+	}
 
-		if unitFlag != "guess" && unitFlag != unit {
-			return "", "", fmt.Errorf("mismatch between unit flag (%v) and input unit (%v)", unitFlag, unit)
-		}
+	if i, err := strconv.ParseInt(nowFlag, 10, 64); err == nil {
+		return epoch.FixedClock(time.Unix(i, 0).In(loc)), nil
+	}
 
-		return inputTrim, unit, nil
+	t, _, err := epoch.ParseFormatted(nowFlag, loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse now '%v': %w", nowFlag, err)
 	}
-	return input, unitFlag, nil
+	return epoch.FixedClock(t), nil
 }
 
-func timestamp(t time.Time, unitFlag string, quiete bool) int64 {
-	unit, err := epoch.ParseUnit(unitFlag)
-	if err != nil {
-		// use seconds as default unit
-		unit = epoch.UnitSeconds
-		if !quiete {
-			fmt.Println("using seconds as unit")
+// calendar creates the calendar for business day calculations.
+func calendar(weekendFlag, holidaysFlag string) (*epoch.Calendar, error) {
+	var cal epoch.Calendar
+
+	for _, name := range strings.Split(weekendFlag, ",") {
+		if strings.TrimSpace(name) == "none" {
+			continue
+		}
+		if strings.TrimSpace(name) == "" {
+			continue
 		}
+		day, err := epoch.ParseWeekday(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		cal.Weekend = append(cal.Weekend, day)
 	}
+	cal.NoWeekend = len(cal.Weekend) == 0
 
-	// convert time to timestamp
-	timestamp, err := epoch.ToTimestamp(t, unit)
-	if err != nil {
-		log.Fatalf("failed to convert timestamp: %v", err)
-	}
-	return timestamp
-}
+	for _, name := range strings.Split(holidaysFlag, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
 
-func parseTimestamp(unitFlag string, i int64, quiete bool) time.Time {
-	unit, err := epoch.ParseUnit(unitFlag)
-	if err != nil {
-		unit = epoch.GuessUnit(i, time.Now())
-
-		if !quiete {
-			switch unit {
-			case epoch.UnitSeconds:
-				fmt.Fprintln(os.Stderr, "guessed unit: seconds")
-			case epoch.UnitMilliseconds:
-				fmt.Fprintln(os.Stderr, "guessed unit: milliseconds")
-			case epoch.UnitMicroseconds:
-				fmt.Fprintln(os.Stderr, "guessed unit: microseconds")
-			case epoch.UnitNanoseconds:
-				fmt.Fprintln(os.Stderr, "guessed unit: nanoseconds")
+		// prefer files, otherwise a file named e.g. 'de' couldn't be used
+		if _, err := os.Stat(name); err != nil {
+			holidays, err := epoch.BuiltinHolidays(name)
+			if err != nil {
+				return nil, err
 			}
+			cal.Holidays = append(cal.Holidays, holidays...)
+			continue
 		}
-	}
 
-	t, err := epoch.ParseTimestamp(i, unit)
-	if err != nil {
-		log.Fatalf("failed to convert from timestamp: %v", err)
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open holidays: %v", err)
+		}
+		holidays, err := epoch.LoadHolidays(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load holidays from '%v': %v", name, err)
+		}
+		cal.Holidays = append(cal.Holidays, holidays...)
 	}
-	return t
-}
 
-func location(tz string) *time.Location {
-	if strings.ToLower(tz) == "local" || tz == "" {
-		tz = "Local" // capital is important
-	}
+	return &cal, nil
+}
 
-	loc, err := time.LoadLocation(tz)
-	if err != nil {
-		log.Fatalf("failed loading timezone '%v': %v\n", tz, err)
-	}
-	return loc
+// isFlagSet reports whether the flag with the given name was passed on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
@@ -1,66 +1,156 @@
 package main
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/sj14/epoch/pkg/epoch"
 )
 
-func TestRun(t *testing.T) {
-	type args struct {
-		input      string
-		now        string
-		unitFlag   string
-		formatFlag string
-		tzFlag     string
-		quietFlag  bool
-		calc       string
+func TestExitCode(t *testing.T) {
+	_, _, ambiguous := epoch.ParseFormatted("03/04/2024", time.UTC)
+	_, unknownTimezone := epoch.LoadLocation("Mars/Olympus")
+	_, unknownUnit := epoch.ParseUnit("weeks")
+	_, outOfRange := epoch.ToTimestamp(time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC), epoch.UnitNanoseconds)
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "parse", err: fmt.Errorf("failed to convert input: %w", epoch.ErrParseFormatted), want: exitParse},
+		{name: "ambiguous", err: ambiguous, want: exitParse},
+		{name: "no time", err: epoch.ErrNoTime, want: exitParse},
+		{name: "unknown unit", err: unknownUnit, want: exitUnknownUnit},
+		{name: "unknown timezone", err: unknownTimezone, want: exitUnknownTimezone},
+		{name: "out of range", err: outOfRange, want: exitOutOfRange},
+		{name: "expiring", err: expiringError([]string{"example.com"}, "+30D"), want: exitExpiring},
+		{name: "other", err: epoch.ErrParseCron, want: exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPinClock(t *testing.T) {
+	berlin, err := epoch.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
 	}
+
 	tests := []struct {
 		name    string
-		args    args
-		want    string
+		now     string
+		want    time.Time
 		wantErr bool
 	}{
-		// Remember that timezone "Local" can't be used as the CI might have a different timezone!
-		{name: "empty input", args: args{now: "2020-07-18 17:46:45.215239 +0200 CEST", unitFlag: "guess"}, want: "1595087205"},
-		{name: "empty input/utc", args: args{now: "2020-07-18 17:46:45.215239 +0200 CEST", tzFlag: "UTC", unitFlag: "guess"}, want: "2020-07-18 15:46:45.215239 +0000 UTC"},
-
-		{name: "timedate", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", unitFlag: "guess"}, want: "1595087205"},
-		{name: "timedate/unit", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", unitFlag: "ms"}, want: "1595087205215"},
-		{name: "timedate/timezone", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", tzFlag: "MST", unitFlag: "guess"}, want: "2020-07-18 08:46:45.215239 -0700 MST"},
-		{name: "timedate/timezone/format", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", formatFlag: "unix", tzFlag: "UTC", unitFlag: "guess"}, want: "Sat Jul 18 15:46:45 UTC 2020"},
-		{name: "timedate/timezone/unit/FAIL", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", unitFlag: "ms", formatFlag: "unix", tzFlag: "UTC"}, wantErr: true},
-
-		{name: "timestamp/timezone/unitsuffix", args: args{input: "1595087205us", tzFlag: "Europe/Berlin", unitFlag: "guess"}, want: "1970-01-01 01:26:35.087205 +0100 CET"},
-		{name: "timestamp/timezone/unit", args: args{input: "1595087205", tzFlag: "Europe/Berlin", unitFlag: "ms"}, want: "1970-01-19 12:04:47.205 +0100 CET"},
-		{name: "timestamp/timezone", args: args{input: "1595087205", tzFlag: "UTC", unitFlag: "guess"}, want: "2020-07-18 15:46:45 +0000 UTC"},
-		{name: "timestamp/timezone/format", args: args{input: "1595087205", formatFlag: "ruby", tzFlag: "UTC", unitFlag: "guess"}, want: "Sat Jul 18 15:46:45 +0000 2020"},
-
-		// arithmetics
-		{name: "arithmetics empty input", args: args{input: "", calc: "+1h", now: "2020-07-18 17:46:45.215239 +0200 CEST", unitFlag: "guess"}, want: "1595090805"},
-		{name: "arithmetics empty input/utc", args: args{input: "", calc: "+1h", now: "2020-07-18 17:46:45.215239 +0200 CEST", tzFlag: "UTC", unitFlag: "guess"}, want: "2020-07-18 16:46:45.215239 +0000 UTC"},
-
-		{name: "arithmetics timedate/timezone/add/no_tz", args: args{input: "2020-07-18 17:46:45.215239", calc: "+1h", tzFlag: "MST", unitFlag: "guess"}, want: "2020-07-18 18:46:45.215239 -0700 MST"},
-		{name: "arithmetics timedate/timezone/add", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "+1h", tzFlag: "MST", unitFlag: "guess"}, want: "2020-07-18 09:46:45.215239 -0700 MST"},
-		{name: "arithmetics timedate/timezone/sub", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "-1h", tzFlag: "MST", unitFlag: "guess"}, want: "2020-07-18 07:46:45.215239 -0700 MST"},
-		{name: "arithmetics timedate/timezone/multiple", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "-30m +1h -5D +3W -6M +2Y", tzFlag: "MST", unitFlag: "guess"}, want: "2022-02-03 09:16:45.215239 -0700 MST"},
-		{name: "arithmetics timedate/timezone/format", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "+1h", formatFlag: "unix", tzFlag: "UTC", unitFlag: "guess"}, want: "Sat Jul 18 16:46:45 UTC 2020"},
-		{name: "arithmetics timedate/timezone/unit/FAIL", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "+1h", unitFlag: "ms", formatFlag: "unix", tzFlag: "UTC"}, wantErr: true},
-
-		{name: "arithmetics timestamp/timezone/unitsuffix", args: args{input: "1595087205us", calc: "+1h", tzFlag: "MST", unitFlag: "guess"}, want: "5195087205"},
-		{name: "arithmetics timestamp/timezone/unit", args: args{input: "1595087205", calc: "+1h", tzFlag: "MST", unitFlag: "ms"}, want: "1598687205"},
-		{name: "arithmetics timestamp/timezone/multiple", args: args{input: "1595087205", calc: "-30m +1h -5D +3W -6M +2Y", tzFlag: "MST"}, want: "1643905005"},
+		{name: "formatted", now: "2024-01-01T00:00:00Z", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "formatted/location", now: "2024-01-01 12:00:00", want: time.Date(2024, 1, 1, 12, 0, 0, 0, berlin)},
+		{name: "timestamp", now: "1704067200", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "invalid/FAIL", now: "yesterday", wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := run(tt.args.input, tt.args.now, tt.args.calc, tt.args.unitFlag, tt.args.formatFlag, tt.args.tzFlag, tt.args.quietFlag)
+			got, err := pinClock(tt.now, berlin)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("pinClock() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
-				t.Errorf("run() = %v, want %v", got, tt.want)
+			if err == nil && !got.Now().Equal(tt.want) {
+				t.Errorf("pinClock() = %v, want %v", got.Now(), tt.want)
 			}
+		})
+	}
 
+	// without the flag, the clock doesn't advance during a run
+	clock, err := pinClock("", berlin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first := clock.Now(); !clock.Now().Equal(first) {
+		t.Errorf("pinClock() isn't fixed")
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	tests := []struct {
+		name          string
+		weekend       string
+		want          []time.Weekday
+		wantNoWeekend bool
+		wantErr       bool
+	}{
+		{name: "default", weekend: "sat,sun", want: []time.Weekday{time.Saturday, time.Sunday}},
+		{name: "none", weekend: "none", wantNoWeekend: true},
+		{name: "empty", weekend: "", wantNoWeekend: true},
+		{name: "invalid/FAIL", weekend: "sat,someday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calendar(tt.weekend, "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("calendar() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got.Weekend, tt.want) {
+				t.Errorf("calendar() weekend = %#v, want %#v", got.Weekend, tt.want)
+			}
+			if err == nil && got.NoWeekend != tt.wantNoWeekend {
+				t.Errorf("calendar() no weekend = %v, want %v", got.NoWeekend, tt.wantNoWeekend)
+			}
 		})
 	}
+
+	// Saturday is a business day without weekend days
+	cal, err := calendar("none", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cal.IsBusinessDay(time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("calendar() without weekend skips Saturday")
+	}
+}
+
+// testConverter creates the converter like the flags of the command, the current time is given by now.
+func testConverter(t *testing.T, now, unitFlag, formatFlag, tzFlag, calc string, calculator epoch.Calculator) *epoch.Converter {
+	t.Helper()
+
+	calculations, err := epoch.ParseCalculations(calc)
+	if err != nil {
+		t.Fatalf("failed to parse calculations '%v': %v", calc, err)
+	}
+
+	options := []epoch.ConverterOption{epoch.WithFormat(formatFlag), epoch.WithCalculations(calculations...), epoch.WithCalculator(calculator)}
+
+	if now != "" {
+		ref, _, err := epoch.ParseFormatted(now, time.UTC)
+		if err != nil {
+			t.Fatalf("failed to parse now '%v': %v", now, err)
+		}
+		options = append(options, epoch.WithClock(epoch.FixedClock(ref)))
+	}
+
+	if unitFlag != "guess" {
+		unit, err := epoch.ParseUnit(unitFlag)
+		if err != nil {
+			t.Fatalf("failed to parse unit '%v': %v", unitFlag, err)
+		}
+		options = append(options, epoch.WithUnit(unit))
+	}
+
+	if tzFlag != "" {
+		loc, err := epoch.LoadLocation(tzFlag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		options = append(options, epoch.WithLocation(loc))
+	}
+
+	return epoch.NewConverter(options...)
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// runMetadata lists the embedded times of the tar and zip archives and JPEG and TIFF images in the files,
+// or in stdin without files. Times without timezone are interpreted in the location of the converter.
+func runMetadata(paths []string, stdin io.Reader, converter *epoch.Converter) ([]string, error) {
+	var fields []epoch.Field
+	if len(paths) == 0 {
+		var err error
+		fields, err = epoch.ReadMetadataStream(stdin, converter.Location())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, path := range paths {
+		fileFields, err := readMetadataFile(path, converter.Location())
+		if err != nil {
+			return nil, err
+		}
+
+		// the names of entries and tags are ambiguous between files
+		for _, field := range fileFields {
+			if len(paths) > 1 {
+				field.Name = fmt.Sprintf("%v %v", path, field.Name)
+			}
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no times found", epoch.ErrMetadata)
+	}
+	return renderFields(fields, converter)
+}
+
+// readMetadataFile reads the embedded times of the file without loading it into memory.
+func readMetadataFile(path string, loc *time.Location) ([]epoch.Field, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := epoch.ReadMetadata(file, info.Size(), loc)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+func TestRunMetadata(t *testing.T) {
+	var tarred bytes.Buffer
+	tarWriter := tar.NewWriter(&tarred)
+	if err := tarWriter.WriteHeader(&tar.Header{Name: "bin/epoch", ModTime: time.Date(2024, 1, 1, 0, 0, 0, 500, time.UTC), Format: tar.FormatPAX}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var zipped bytes.Buffer
+	zipWriter := zip.NewWriter(&zipped)
+	if _, err := zipWriter.CreateHeader(&zip.FileHeader{Name: "README.md", Modified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// only keep the MS-DOS time, the zip package always adds an extended timestamp
+	dosOnly := bytes.ReplaceAll(zipped.Bytes(), []byte{0x55, 0x54, 5, 0, 1}, []byte{0xff, 0xff, 5, 0, 1})
+	zipPath := filepath.Join(t.TempDir(), "epoch.zip")
+	if err := os.WriteFile(zipPath, dosOnly, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		paths    []string
+		stdin    []byte
+		tzFlag   string
+		unitFlag string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{name: "tar/stdin", args: args{stdin: tarred.Bytes(), unitFlag: "ns"}, want: []string{"bin/epoch mtime: 1704067200000000500"}},
+		{name: "zip/timezone", args: args{paths: []string{zipPath}, tzFlag: "Europe/Berlin", unitFlag: "s"}, want: []string{"README.md mtime: 1704063600, MS-DOS time with 2-second precision and without timezone"}},
+		{name: "multiple files", args: args{paths: []string{zipPath, zipPath}, tzFlag: "UTC", unitFlag: "s"}, want: []string{zipPath + " README.md mtime: 1704067200, MS-DOS time with 2-second precision and without timezone", zipPath + " README.md mtime: 1704067200, MS-DOS time with 2-second precision and without timezone"}},
+		{name: "unknown format/FAIL", args: args{stdin: []byte("2024-01-01"), unitFlag: "guess"}, wantErr: true},
+		{name: "missing file/FAIL", args: args{paths: []string{filepath.Join(t.TempDir(), "missing.zip")}, unitFlag: "guess"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runMetadata(tt.args.paths, bytes.NewReader(tt.args.stdin), testConverter(t, "", tt.args.unitFlag, "", tt.args.tzFlag, "", epoch.Calculator{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runMetadata() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("runMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// runRange lists the times from the input time, after the calculations of the converter, in steps (e.g. "+1D") until end (exclusive) or count times.
+// With pairs, each line contains a half-open interval "start<TAB>end" instead.
+func runRange(input, step, end string, count int, pairs bool, converter *epoch.Converter) ([]string, error) {
+	calculations, err := epoch.ParseCalculations(step)
+	if err != nil {
+		return nil, err
+	}
+	if len(calculations) != 1 {
+		return nil, fmt.Errorf("step requires exactly one calculation, e.g. '+1D', got '%v'", step)
+	}
+
+	start, err := converter.ParseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	start, err = converter.Calculate(start)
+	if err != nil {
+		return nil, err
+	}
+
+	var limit time.Time
+	if end != "" {
+		limit, err = converter.ParseInput(end)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lines []string
+
+	if !pairs {
+		times, err := epoch.Sequence(converter.Calculator(), start, calculations[0], limit, count)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range times {
+			line, err := converter.Render(t)
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, line)
+		}
+		return lines, nil
+	}
+
+	intervals, err := epoch.Intervals(converter.Calculator(), start, calculations[0], limit, count)
+	if err != nil {
+		return nil, err
+	}
+	for _, interval := range intervals {
+		from, err := converter.Render(interval.Start)
+		if err != nil {
+			return nil, err
+		}
+		to, err := converter.Render(interval.End)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, from+"\t"+to)
+	}
+	return lines, nil
+}
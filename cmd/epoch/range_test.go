@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+func TestRunRange(t *testing.T) {
+	type args struct {
+		input      string
+		now        string
+		step       string
+		end        string
+		count      int
+		pairs      bool
+		calc       string
+		calculator epoch.Calculator
+		unitFlag   string
+		formatFlag string
+		tzFlag     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{name: "timedate/count", args: args{input: "2024-01-31 12:00:00 +0000 UTC", step: "+1D", count: 2, unitFlag: "guess", tzFlag: "UTC"}, want: []string{"2024-01-31 12:00:00 +0000 UTC", "2024-02-01 12:00:00 +0000 UTC"}},
+		{name: "timedate/end/format", args: args{input: "2024-01-01 00:00:00 +0000 UTC", step: "+6h", end: "2024-01-01 18:00:00 +0000 UTC", unitFlag: "guess", formatFlag: "rfc3339", tzFlag: "UTC"}, want: []string{"2024-01-01T00:00:00Z", "2024-01-01T06:00:00Z", "2024-01-01T12:00:00Z"}},
+		{name: "timedate/months/clamp", args: args{input: "2024-01-31 00:00:00 +0000 UTC", step: "+1M", count: 3, calculator: epoch.Calculator{Overflow: epoch.OverflowClamp}, unitFlag: "guess", formatFlag: "rfc3339", tzFlag: "UTC"}, want: []string{"2024-01-31T00:00:00Z", "2024-02-29T00:00:00Z", "2024-03-31T00:00:00Z"}},
+		{name: "timedate/calc", args: args{input: "2024-06-01 13:00:00 +0000 UTC", step: "+1D", count: 2, calc: "/D", unitFlag: "guess", formatFlag: "rfc3339", tzFlag: "UTC"}, want: []string{"2024-06-01T00:00:00Z", "2024-06-02T00:00:00Z"}},
+		{name: "timestamp/unit", args: args{input: "1704067200", step: "+1h", count: 2, unitFlag: "s", tzFlag: "UTC"}, want: []string{"1704067200", "1704070800"}},
+		{name: "timestamp/end", args: args{input: "1704067200", step: "+1h", end: "1704074400", unitFlag: "s", tzFlag: "UTC"}, want: []string{"1704067200", "1704070800"}},
+		{name: "pairs/end", args: args{input: "2024-01-01 00:00:00 +0000 UTC", step: "+1W", end: "2024-01-10 00:00:00 +0000 UTC", pairs: true, unitFlag: "guess", formatFlag: "rfc3339", tzFlag: "UTC"}, want: []string{"2024-01-01T00:00:00Z\t2024-01-08T00:00:00Z", "2024-01-08T00:00:00Z\t2024-01-10T00:00:00Z"}},
+		{name: "empty input/count", args: args{now: "2024-01-01 00:00:00 +0000 UTC", step: "-1D", count: 2, unitFlag: "guess", formatFlag: "rfc3339", tzFlag: "UTC"}, want: []string{"2024-01-01T00:00:00Z", "2023-12-31T00:00:00Z"}},
+		{name: "multiple steps/FAIL", args: args{input: "1704067200", step: "+1h +1D", count: 2, unitFlag: "s", tzFlag: "UTC"}, wantErr: true},
+		{name: "truncate step/FAIL", args: args{input: "1704067200", step: "/D", count: 2, unitFlag: "s", tzFlag: "UTC"}, wantErr: true},
+		{name: "unbounded/FAIL", args: args{input: "1704067200", step: "+1D", unitFlag: "s", tzFlag: "UTC"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runRange(tt.args.input, tt.args.step, tt.args.end, tt.args.count, tt.args.pairs, testConverter(t, tt.args.now, tt.args.unitFlag, tt.args.formatFlag, tt.args.tzFlag, tt.args.calc, tt.args.calculator))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("runRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
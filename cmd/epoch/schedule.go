@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// runSchedule lists the next (or previous) occurrences of a cron expression, RRULE or systemd calendar expression
+// relative to the input time after the calculations of the converter.
+func runSchedule(input, cron, rrule, onCalendar string, count int, prev bool, converter *epoch.Converter) ([]string, error) {
+	if (cron != "" && rrule != "") || (cron != "" && onCalendar != "") || (rrule != "" && onCalendar != "") {
+		return nil, fmt.Errorf("can't use more than one of the cron, rrule and oncalendar flags")
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("count has to be positive")
+	}
+
+	ref, err := converter.ParseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	ref, err = converter.Calculate(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule epoch.Schedule
+	switch {
+	case cron != "":
+		schedule, err = epoch.ParseCron(cron, converter.Location())
+	case onCalendar != "":
+		schedule, err = epoch.ParseOnCalendar(onCalendar, converter.Location())
+	default:
+		schedule, err = epoch.ParseRRule(rrule, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if prev {
+		count = -count
+	}
+
+	occurrences, err := epoch.Occurrences(schedule, ref, count)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, t := range occurrences {
+		line, err := converter.Render(t.In(converter.Location()))
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
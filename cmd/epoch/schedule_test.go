@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+func TestRunSchedule(t *testing.T) {
+	type args struct {
+		input      string
+		now        string
+		cron       string
+		rrule      string
+		onCalendar string
+		count      int
+		prev       bool
+		calc       string
+		calculator epoch.Calculator
+		unitFlag   string
+		formatFlag string
+		tzFlag     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{name: "cron/timedate", args: args{input: "2024-03-29 10:00:00 +0100 CET", cron: "30 9 * * Mon-Fri", count: 2, unitFlag: "guess", tzFlag: "Europe/Berlin"}, want: []string{"2024-04-01 09:30:00 +0200 CEST", "2024-04-02 09:30:00 +0200 CEST"}},
+		{name: "cron/timedate/prev", args: args{input: "2024-03-29 10:00:00 +0100 CET", cron: "30 9 * * Mon-Fri", count: 2, prev: true, unitFlag: "guess", tzFlag: "Europe/Berlin"}, want: []string{"2024-03-29 09:30:00 +0100 CET", "2024-03-28 09:30:00 +0100 CET"}},
+		{name: "cron/timestamp/unit", args: args{input: "1711702800", cron: "0 12 * * *", count: 1, unitFlag: "s", tzFlag: "UTC"}, want: []string{"1711713600"}},
+		{name: "cron/empty input/format", args: args{now: "2024-03-29 10:00:00 +0000 UTC", cron: "0 12 * * *", count: 1, unitFlag: "guess", formatFlag: "rfc3339", tzFlag: "UTC"}, want: []string{"2024-03-29T12:00:00Z"}},
+		{name: "cron/calc/holidays", args: args{input: "2024-03-29 10:00:00 +0000 UTC", cron: "0 12 * * *", count: 1, calc: "+1B", calculator: epoch.Calculator{Calendar: &epoch.Calendar{Holidays: []epoch.Holiday{{Month: time.April, Day: 1}}}}, unitFlag: "guess", formatFlag: "rfc3339", tzFlag: "UTC"}, want: []string{"2024-04-02T12:00:00Z"}},
+		{name: "rrule/timedate", args: args{input: "2024-01-01 09:00:00 +0000 UTC", rrule: "FREQ=MONTHLY;BYDAY=-1FR", count: 2, unitFlag: "guess", tzFlag: "UTC"}, want: []string{"2024-01-26 09:00:00 +0000 UTC", "2024-02-23 09:00:00 +0000 UTC"}},
+		{name: "rrule/dtstart/prev", args: args{input: "2024-01-01 00:00:00 +0000 UTC", rrule: "DTSTART:20230101T120000Z RRULE:FREQ=MONTHLY", count: 2, prev: true, unitFlag: "guess", tzFlag: "UTC"}, want: []string{"2023-12-01 12:00:00 +0000 UTC", "2023-11-01 12:00:00 +0000 UTC"}},
+		{name: "oncalendar/timedate", args: args{input: "2024-03-29 10:00:00 +0100 CET", onCalendar: "Mon..Fri *-*-* 09:00:00 Europe/Berlin", count: 2, unitFlag: "guess", formatFlag: "rfc3339", tzFlag: "UTC"}, want: []string{"2024-04-01T07:00:00Z", "2024-04-02T07:00:00Z"}},
+		{name: "oncalendar/timezone flag/prev", args: args{input: "2024-03-29 10:00:00 +0100 CET", onCalendar: "*-*-01 00:00", count: 2, prev: true, unitFlag: "guess", tzFlag: "Europe/Berlin"}, want: []string{"2024-03-01 00:00:00 +0100 CET", "2024-02-01 00:00:00 +0100 CET"}},
+		{name: "cron and oncalendar/FAIL", args: args{input: "1711702800", cron: "0 12 * * *", onCalendar: "daily", count: 1, unitFlag: "guess", tzFlag: "UTC"}, wantErr: true},
+		{name: "invalid oncalendar/FAIL", args: args{input: "1711702800", onCalendar: "Mon..Fre", count: 1, unitFlag: "guess", tzFlag: "UTC"}, wantErr: true},
+		{name: "cron and rrule/FAIL", args: args{input: "1711702800", cron: "0 12 * * *", rrule: "FREQ=DAILY", count: 1, unitFlag: "guess", tzFlag: "UTC"}, wantErr: true},
+		{name: "invalid cron/FAIL", args: args{input: "1711702800", cron: "0 12 * *", count: 1, unitFlag: "guess", tzFlag: "UTC"}, wantErr: true},
+		{name: "never/FAIL", args: args{input: "1711702800", cron: "0 0 30 2 *", count: 1, unitFlag: "guess", tzFlag: "UTC"}, wantErr: true},
+		{name: "rrule/never/FAIL", args: args{input: "1711702800", rrule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", count: 1, unitFlag: "guess", tzFlag: "UTC"}, wantErr: true},
+		{name: "invalid count/FAIL", args: args{input: "1711702800", cron: "0 12 * * *", count: 0, unitFlag: "guess", tzFlag: "UTC"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runSchedule(tt.args.input, tt.args.cron, tt.args.rrule, tt.args.onCalendar, tt.args.count, tt.args.prev, testConverter(t, tt.args.now, tt.args.unitFlag, tt.args.formatFlag, tt.args.tzFlag, tt.args.calc, tt.args.calculator))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runSchedule() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("runSchedule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// runStat lists the modification, access, change and birth times of the files.
+// With setTimes, the access and modification times are set to the parsed input after the calculations first.
+func runStat(paths []string, setTimes string, converter *epoch.Converter) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("stat requires file arguments")
+	}
+
+	if setTimes != "" {
+		t, err := converter.ParseInput(setTimes)
+		if err != nil {
+			return nil, err
+		}
+		t, err = converter.Calculate(t)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			if err := epoch.SetFileTimes(path, t, t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var lines []string
+	for _, path := range paths {
+		times, err := epoch.StatFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fieldLines, err := renderFields(times.Fields(), converter)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fieldLines...)
+	}
+	return lines, nil
+}
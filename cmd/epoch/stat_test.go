@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+func TestRunStat(t *testing.T) {
+	type args struct {
+		paths    []string
+		setTimes string
+		calc     string
+		unitFlag string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{name: "set timestamp", args: args{setTimes: "1704067200123456789", unitFlag: "ns"}, want: []string{"1704067200123456789", "1704067200123456789"}},
+		{name: "set formatted", args: args{setTimes: "2024-01-01T00:00:00.5Z", unitFlag: "ms"}, want: []string{"1704067200500", "1704067200500"}},
+		{name: "set calc", args: args{setTimes: "2024-01-01T13:00:00Z", calc: "/D +1h", unitFlag: "s"}, want: []string{"1704070800", "1704070800"}},
+		{name: "set invalid/FAIL", args: args{setTimes: "yesterday", unitFlag: "guess"}, wantErr: true},
+		{name: "missing file/FAIL", args: args{paths: []string{filepath.Join(t.TempDir(), "missing")}, unitFlag: "guess"}, wantErr: true},
+		{name: "no files/FAIL", args: args{paths: []string{}, unitFlag: "guess"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := tt.args.paths
+			if paths == nil {
+				paths = []string{filepath.Join(t.TempDir(), "file")}
+				if err := os.WriteFile(paths[0], nil, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := runStat(paths, tt.args.setTimes, testConverter(t, "", tt.args.unitFlag, "", "UTC", tt.args.calc, epoch.Calculator{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runStat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			// the change and birth times are the current time
+			if len(got) != 4 {
+				t.Fatalf("runStat() = %v, want 4 times", got)
+			}
+			want := []string{paths[0] + " mtime: " + tt.want[0], paths[0] + " atime: " + tt.want[1]}
+			if !reflect.DeepEqual(got[:2], want) {
+				t.Errorf("runStat() = %v, want %v", got[:2], want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// maxLineSize is the maximum size of lines of the stream flag, journal entries can be large.
+const maxLineSize = 1 << 20
+
+// runStream converts each line of r, empty lines are skipped. Journal entries of "journalctl -o json" are
+// converted by their __REALTIME_TIMESTAMP and followed by their MESSAGE. Lines which can't be converted
+// are reported to errOut, the first error is returned after the last line.
+func runStream(r io.Reader, w, errOut io.Writer, converter *epoch.Converter) error {
+	var (
+		firstErr       error
+		failed, number int
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		number++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		output, err := streamLine(line, converter)
+		if err != nil {
+			fmt.Fprintf(errOut, "line %v: %v\n", number, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			continue
+		}
+		fmt.Fprintln(w, output)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %v", err)
+	}
+
+	if firstErr != nil {
+		return fmt.Errorf("failed to convert %v of %v lines: %w", failed, number, firstErr)
+	}
+	return nil
+}
+
+func streamLine(line string, converter *epoch.Converter) (string, error) {
+	if !epoch.IsJournalEntry(line) {
+		return converter.Convert(line)
+	}
+
+	entry, err := epoch.ParseJournalEntry(line)
+	if err != nil {
+		return "", err
+	}
+	output, err := converter.Render(entry.Realtime.In(converter.Location()))
+	if err != nil {
+		return "", err
+	}
+	if entry.Message != "" {
+		output += " " + entry.Message
+	}
+	return output, nil
+}
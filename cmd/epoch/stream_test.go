@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+func TestRunStream(t *testing.T) {
+	type args struct {
+		input      string
+		unitFlag   string
+		formatFlag string
+		tzFlag     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantOut string
+		wantErr bool
+	}{
+		{name: "journal", args: args{input: `{"__REALTIME_TIMESTAMP":"1711706400123456","MESSAGE":"Started Daily Cleanup."}` + "\n" + `{"__REALTIME_TIMESTAMP":"1711706401000000"}`, unitFlag: "guess", formatFlag: "rfc3339nano", tzFlag: "UTC"}, want: "2024-03-29T10:00:00.123456Z Started Daily Cleanup.\n2024-03-29T10:00:01Z\n"},
+		{name: "journal/unit", args: args{input: `{"__REALTIME_TIMESTAMP":"1711706400123456","MESSAGE":"hi"}`, unitFlag: "ms"}, want: "1711706400123 hi\n"},
+		{name: "mixed", args: args{input: "1711706400\n\n2024-03-29T10:00:00Z\n", unitFlag: "guess", tzFlag: "UTC"}, want: "2024-03-29 10:00:00 +0000 UTC\n2024-03-29 10:00:00 +0000 UTC\n"},
+		{name: "invalid line/FAIL", args: args{input: "1711706400\nyesterday\n" + `{"__REALTIME_TIMESTAMP":"now"}` + "\n1711706401", unitFlag: "s", tzFlag: "UTC"}, want: "2024-03-29 10:00:00 +0000 UTC\n2024-03-29 10:00:01 +0000 UTC\n", wantOut: "line 2: failed to convert string to time: 'yesterday' doesn't match any of 18 layouts\nline 3: failed to parse journal entry: invalid __REALTIME_TIMESTAMP 'now'\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w, errOut bytes.Buffer
+			err := runStream(strings.NewReader(tt.args.input), &w, &errOut, testConverter(t, "", tt.args.unitFlag, tt.args.formatFlag, tt.args.tzFlag, "", epoch.Calculator{}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runStream() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if w.String() != tt.want {
+				t.Errorf("runStream() = %q, want %q", w.String(), tt.want)
+			}
+			if errOut.String() != tt.wantOut {
+				t.Errorf("runStream() errors = %q, want %q", errOut.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// errExpiring is returned when a certificate, CRL or OCSP response is expired or expires within the expires-within flag.
+var errExpiring = errors.New("expired or expiring")
+
+// runX509 lists the validity of the certificates, CRLs and OCSP responses in the files, or in stdin without files.
+// It returns the subjects expiring before now plus the expiresWithin calculation, already expired ones included.
+func runX509(paths []string, stdin io.Reader, expiresWithin string, converter *epoch.Converter) ([]string, []string, error) {
+	calculations, err := epoch.ParseCalculations(expiresWithin)
+	if err != nil {
+		return nil, nil, err
+	}
+	threshold, err := converter.Calculator().Apply(converter.Now(), calculations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var inputs [][]byte
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		inputs = append(inputs, data)
+	}
+	if len(paths) == 0 {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read input: %v", err)
+		}
+		inputs = append(inputs, data)
+	}
+
+	var lines, expiring []string
+	for i, data := range inputs {
+		validities, err := epoch.ParseValidity(data)
+		if err != nil && len(paths) > 0 {
+			return nil, nil, fmt.Errorf("%v: %w", paths[i], err)
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		for _, validity := range validities {
+			fieldLines, err := renderFields(validity.Fields(), converter)
+			if err != nil {
+				return nil, nil, err
+			}
+			lines = append(lines, fieldLines...)
+
+			if end := validity.End.Time; !end.IsZero() && !end.After(threshold) {
+				expiring = append(expiring, validity.Subject)
+			}
+		}
+	}
+	return lines, expiring, nil
+}
+
+// expiringError returns the error for the exit code of expiring certificates.
+func expiringError(expiring []string, expiresWithin string) error {
+	if expiresWithin == "" {
+		return fmt.Errorf("%w: %v", errExpiring, strings.Join(expiring, ", "))
+	}
+	return fmt.Errorf("%w within '%v': %v", errExpiring, expiresWithin, strings.Join(expiring, ", "))
+}
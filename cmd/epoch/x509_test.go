@@ -0,0 +1,95 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sj14/epoch/pkg/epoch"
+)
+
+// writeCertificate writes a self-signed PEM certificate valid in 2024 to a temporary file.
+func writeCertificate(t *testing.T, commonName string) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), commonName+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunX509(t *testing.T) {
+	cert := writeCertificate(t, "example.com")
+	data, err := os.ReadFile(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		paths         []string
+		stdin         string
+		expiresWithin string
+		now           string
+		formatFlag    string
+		unitFlag      string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		want         []string
+		wantExpiring []string
+		wantErr      bool
+	}{
+		{name: "file", args: args{paths: []string{cert}, now: "2024-06-01T00:00:00Z", formatFlag: "rfc3339", unitFlag: "guess"}, want: []string{"example.com NotBefore: 2024-01-01T00:00:00Z", "example.com NotAfter: 2025-01-01T00:00:00Z"}},
+		{name: "stdin/unit", args: args{stdin: string(data), now: "2024-06-01T00:00:00Z", unitFlag: "s"}, want: []string{"example.com NotBefore: 1704067200", "example.com NotAfter: 1735689600"}},
+		{name: "multiple files", args: args{paths: []string{cert, writeCertificate(t, "example.org")}, now: "2024-06-01T00:00:00Z", unitFlag: "s"}, want: []string{"example.com NotBefore: 1704067200", "example.com NotAfter: 1735689600", "example.org NotBefore: 1704067200", "example.org NotAfter: 1735689600"}},
+		{name: "expires within", args: args{paths: []string{cert}, expiresWithin: "+1M", now: "2024-12-15T00:00:00Z", unitFlag: "s"}, want: []string{"example.com NotBefore: 1704067200", "example.com NotAfter: 1735689600"}, wantExpiring: []string{"example.com"}},
+		{name: "expires later", args: args{paths: []string{cert}, expiresWithin: "+1M", now: "2024-11-15T00:00:00Z", unitFlag: "s"}, want: []string{"example.com NotBefore: 1704067200", "example.com NotAfter: 1735689600"}},
+		{name: "expired", args: args{paths: []string{cert}, now: "2025-01-01T00:00:00Z", unitFlag: "s"}, want: []string{"example.com NotBefore: 1704067200", "example.com NotAfter: 1735689600"}, wantExpiring: []string{"example.com"}},
+		{name: "no certificate/FAIL", args: args{stdin: "2024-01-01", unitFlag: "guess"}, wantErr: true},
+		{name: "missing file/FAIL", args: args{paths: []string{filepath.Join(t.TempDir(), "missing.pem")}, unitFlag: "guess"}, wantErr: true},
+		{name: "threshold/FAIL", args: args{paths: []string{cert}, expiresWithin: "30D", unitFlag: "guess"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter := testConverter(t, tt.args.now, tt.args.unitFlag, tt.args.formatFlag, "UTC", "", epoch.Calculator{})
+			got, expiring, err := runX509(tt.args.paths, strings.NewReader(tt.args.stdin), tt.args.expiresWithin, converter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runX509() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("runX509() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(expiring, tt.wantExpiring) {
+				t.Errorf("runX509() expiring = %v, want %v", expiring, tt.wantExpiring)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package epoch
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Holiday describes a non-working day.
+// Either Day is set for a fixed date (e.g. December 25th) or
+// Weekday and Nth are set for a rule (e.g. the 4th Thursday of November).
+type Holiday struct {
+	Name string
+	// Year restricts the holiday to a single year, 0 means every year.
+	Year  int
+	Month time.Month
+	// Day of the month for fixed dates.
+	Day int
+	// Weekday and Nth are used when Day is 0. Nth counts from the end of the month when negative (-1 is the last).
+	Weekday time.Weekday
+	Nth     int
+}
+
+// On reports whether the holiday is on the given date.
+func (h Holiday) On(year int, month time.Month, day int) bool {
+	if h.Year != 0 && h.Year != year {
+		return false
+	}
+	if h.Month != month {
+		return false
+	}
+	if h.Day != 0 {
+		return h.Day == day
+	}
+
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if date.Weekday() != h.Weekday {
+		return false
+	}
+	if h.Nth > 0 {
+		return (day-1)/7+1 == h.Nth
+	}
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return -((daysInMonth-day)/7 + 1) == h.Nth
+}
+
+// builtinHolidays are fixed-rule holiday sets. Holidays depending on Easter
+// and substitute days for holidays on weekends are not included.
+var builtinHolidays = map[string][]Holiday{
+	"de": {
+		{Name: "New Year's Day", Month: time.January, Day: 1},
+		{Name: "Labour Day", Month: time.May, Day: 1},
+		{Name: "German Unity Day", Month: time.October, Day: 3},
+		{Name: "Christmas Day", Month: time.December, Day: 25},
+		{Name: "Boxing Day", Month: time.December, Day: 26},
+	},
+	"fr": {
+		{Name: "New Year's Day", Month: time.January, Day: 1},
+		{Name: "Labour Day", Month: time.May, Day: 1},
+		{Name: "Victory in Europe Day", Month: time.May, Day: 8},
+		{Name: "Bastille Day", Month: time.July, Day: 14},
+		{Name: "Assumption Day", Month: time.August, Day: 15},
+		{Name: "All Saints' Day", Month: time.November, Day: 1},
+		{Name: "Armistice Day", Month: time.November, Day: 11},
+		{Name: "Christmas Day", Month: time.December, Day: 25},
+	},
+	"uk": {
+		{Name: "New Year's Day", Month: time.January, Day: 1},
+		{Name: "Early May Bank Holiday", Month: time.May, Weekday: time.Monday, Nth: 1},
+		{Name: "Spring Bank Holiday", Month: time.May, Weekday: time.Monday, Nth: -1},
+		{Name: "Summer Bank Holiday", Month: time.August, Weekday: time.Monday, Nth: -1},
+		{Name: "Christmas Day", Month: time.December, Day: 25},
+		{Name: "Boxing Day", Month: time.December, Day: 26},
+	},
+	"us": {
+		{Name: "New Year's Day", Month: time.January, Day: 1},
+		{Name: "Martin Luther King Jr. Day", Month: time.January, Weekday: time.Monday, Nth: 3},
+		{Name: "Washington's Birthday", Month: time.February, Weekday: time.Monday, Nth: 3},
+		{Name: "Memorial Day", Month: time.May, Weekday: time.Monday, Nth: -1},
+		{Name: "Juneteenth", Month: time.June, Day: 19},
+		{Name: "Independence Day", Month: time.July, Day: 4},
+		{Name: "Labor Day", Month: time.September, Weekday: time.Monday, Nth: 1},
+		{Name: "Columbus Day", Month: time.October, Weekday: time.Monday, Nth: 2},
+		{Name: "Veterans Day", Month: time.November, Day: 11},
+		{Name: "Thanksgiving Day", Month: time.November, Weekday: time.Thursday, Nth: 4},
+		{Name: "Christmas Day", Month: time.December, Day: 25},
+	},
+}
+
+// ErrUnknownHolidays is returned when no built-in holiday set with the given name exists.
+var ErrUnknownHolidays = errors.New("unknown holiday set")
+
+// BuiltinHolidays returns the built-in holiday set with the given name ("de", "fr", "uk" or "us").
+// Only holidays following fixed rules are included, e.g. holidays depending on Easter are missing.
+func BuiltinHolidays(name string) ([]Holiday, error) {
+	holidays, ok := builtinHolidays[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%v'", ErrUnknownHolidays, name)
+	}
+	return slices.Clone(holidays), nil
+}
+
+// LoadHolidays reads holidays from an iCalendar file or a simple YAML list of dates.
+//
+// The YAML list contains one date per line, either a specific date ("- 2024-12-24")
+// or a date recurring every year ("- 12-24"). A trailing comment is used as the name.
+// From iCalendar files, the DTSTART date of each event is used. Events with a
+// yearly RRULE recur every year, other rules are ignored.
+func LoadHolidays(r io.Reader) ([]Holiday, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read holidays: %w", err)
+	}
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.TrimSpace(line) == "BEGIN:VCALENDAR" {
+			return parseICalHolidays(lines)
+		}
+		break
+	}
+	return parseYAMLHolidays(lines)
+}
+
+func parseYAMLHolidays(lines []string) ([]Holiday, error) {
+	var holidays []Holiday
+
+	for i, line := range lines {
+		line, name, _ := strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+
+		value, found := strings.CutPrefix(line, "-")
+		if !found {
+			// ignore empty lines and keys such as "holidays:"
+			if line == "" || strings.HasSuffix(line, ":") {
+				continue
+			}
+			return nil, fmt.Errorf("line %v: expected a list entry: '%v'", i+1, line)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		holiday := Holiday{Name: strings.TrimSpace(name)}
+
+		if date, err := time.Parse("2006-01-02", value); err == nil {
+			holiday.Year, holiday.Month, holiday.Day = date.Date()
+		} else if date, err := time.Parse("01-02", value); err == nil {
+			_, holiday.Month, holiday.Day = date.Date()
+		} else {
+			return nil, fmt.Errorf("line %v: failed to parse date '%v' (use YYYY-MM-DD or MM-DD)", i+1, value)
+		}
+
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays, nil
+}
+
+func parseICalHolidays(lines []string) ([]Holiday, error) {
+	var (
+		holidays []Holiday
+		current  *Holiday
+		yearly   bool
+		unfolded []string
+	)
+
+	// unfold long lines (RFC 5545 section 3.1)
+	for _, line := range lines {
+		if len(unfolded) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+
+	for i, line := range unfolded {
+		nameAndParams, value, _ := strings.Cut(line, ":")
+		name, _, _ := strings.Cut(nameAndParams, ";")
+
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if value == "VEVENT" {
+				current, yearly = &Holiday{}, false
+			}
+		case "END":
+			if value != "VEVENT" || current == nil {
+				continue
+			}
+			if current.Month == 0 {
+				return nil, fmt.Errorf("line %v: event without DTSTART", i+1)
+			}
+			if yearly {
+				current.Year = 0
+			}
+			holidays = append(holidays, *current)
+			current = nil
+		case "SUMMARY":
+			if current != nil {
+				current.Name = value
+			}
+		case "RRULE":
+			if current != nil && strings.Contains(strings.ToUpper(value), "FREQ=YEARLY") {
+				yearly = true
+			}
+		case "DTSTART":
+			if current == nil {
+				continue
+			}
+			if len(value) < 8 {
+				return nil, fmt.Errorf("line %v: failed to parse DTSTART '%v'", i+1, value)
+			}
+			date, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("line %v: failed to parse DTSTART '%v': %w", i+1, value, err)
+			}
+			// the date is used as is, independent of a TZID parameter
+			current.Year, current.Month, current.Day = date.Date()
+		}
+	}
+
+	return holidays, nil
+}
+
+// ParseWeekday returns the weekday of the given English name or abbreviation (e.g. "Mon" or "monday").
+func ParseWeekday(s string) (time.Weekday, error) {
+	lower := strings.ToLower(s)
+	if len(lower) >= 3 {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.HasPrefix(strings.ToLower(day.String()), lower) {
+				return day, nil
+			}
+		}
+	}
+	return time.Sunday, fmt.Errorf("failed to parse weekday '%v'", s)
+}
+
+// Calendar defines which days are business days.
+// The zero value uses Saturday and Sunday as weekend without any holidays.
+type Calendar struct {
+	// Weekend contains the non-working days of the week. Saturday and Sunday are used when empty.
+	Weekend []time.Weekday
+	// NoWeekend treats every day of the week as a working day, e.g. for calendars of 7-day operations.
+	// Weekend is ignored when set.
+	NoWeekend bool
+	// Holidays are non-working days in addition to the weekend.
+	Holidays []Holiday
+}
+
+// weekend returns the non-working days of the week.
+func (c Calendar) weekend() []time.Weekday {
+	switch {
+	case c.NoWeekend:
+		return nil
+	case len(c.Weekend) == 0:
+		return []time.Weekday{time.Saturday, time.Sunday}
+	}
+	return c.Weekend
+}
+
+// ErrNoBusinessDays is returned when the calendar has no business days at all.
+var ErrNoBusinessDays = errors.New("calendar has no business days")
+
+// IsBusinessDay reports whether the date of t is neither on the weekend nor a holiday.
+func (c Calendar) IsBusinessDay(t time.Time) bool {
+	if slices.Contains(c.weekend(), t.Weekday()) {
+		return false
+	}
+
+	year, month, day := t.Date()
+	for _, holiday := range c.Holidays {
+		if holiday.On(year, month, day) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddBusinessDays adds (or subtracts when negative) the amount of business days to t while keeping the time of day.
+// Starting on a non-business day, +1 returns the next business day.
+func (c Calendar) AddBusinessDays(t time.Time, amount int) (time.Time, error) {
+	weekend := map[time.Weekday]bool{}
+	for _, day := range c.weekend() {
+		weekend[day] = true
+	}
+	if len(weekend) == 7 {
+		return time.Time{}, ErrNoBusinessDays
+	}
+
+	step := 1
+	if amount < 0 {
+		step, amount = -1, -amount
+	}
+
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+
+	// holidays might cover whole years, give up at some point instead of looping forever
+	for skipped := 0; amount > 0; {
+		day += step
+		date := time.Date(year, month, day, hour, min, sec, t.Nanosecond(), t.Location())
+		if c.IsBusinessDay(date) {
+			amount--
+			skipped = 0
+			continue
+		}
+		skipped++
+		if skipped > 366*2 {
+			return time.Time{}, ErrNoBusinessDays
+		}
+	}
+
+	return time.Date(year, month, day, hour, min, sec, t.Nanosecond(), t.Location()), nil
+}
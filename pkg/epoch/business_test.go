@@ -0,0 +1,225 @@
+package epoch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHolidayOn(t *testing.T) {
+	testCases := []struct {
+		description string
+		holiday     Holiday
+		date        time.Time
+		expected    bool
+	}{
+		{
+			description: "fixed date",
+			holiday:     Holiday{Month: time.December, Day: 25},
+			date:        time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+			expected:    true,
+		},
+		{
+			description: "fixed date/other year",
+			holiday:     Holiday{Year: 2023, Month: time.December, Day: 25},
+			date:        time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+			expected:    false,
+		},
+		{
+			description: "nth weekday",
+			holiday:     Holiday{Month: time.November, Weekday: time.Thursday, Nth: 4},
+			date:        time.Date(2024, 11, 28, 0, 0, 0, 0, time.UTC),
+			expected:    true,
+		},
+		{
+			description: "nth weekday/wrong week",
+			holiday:     Holiday{Month: time.November, Weekday: time.Thursday, Nth: 4},
+			date:        time.Date(2024, 11, 21, 0, 0, 0, 0, time.UTC),
+			expected:    false,
+		},
+		{
+			description: "last weekday",
+			holiday:     Holiday{Month: time.May, Weekday: time.Monday, Nth: -1},
+			date:        time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC),
+			expected:    true,
+		},
+		{
+			description: "last weekday/second last",
+			holiday:     Holiday{Month: time.May, Weekday: time.Monday, Nth: -1},
+			date:        time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+			expected:    false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			year, month, day := tt.date.Date()
+			equal(t, tt.holiday.On(year, month, day), tt.expected)
+		})
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	christmas, err := BuiltinHolidays("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	friday := time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description string
+		calendar    Calendar
+		given       time.Time
+		amount      int
+		expected    time.Time
+		expectedErr error
+	}{
+		{
+			description: "zero",
+			given:       friday,
+			expected:    friday,
+		},
+		{
+			description: "skip weekend",
+			given:       friday,
+			amount:      1,
+			expected:    time.Date(2024, 12, 23, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			description: "skip weekend/backwards",
+			given:       time.Date(2024, 12, 23, 9, 30, 0, 0, time.UTC),
+			amount:      -1,
+			expected:    friday,
+		},
+		{
+			description: "start on weekend",
+			given:       time.Date(2024, 12, 21, 9, 30, 0, 0, time.UTC),
+			amount:      1,
+			expected:    time.Date(2024, 12, 23, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			description: "skip holidays",
+			calendar:    Calendar{Holidays: christmas},
+			given:       friday,
+			amount:      3,
+			expected:    time.Date(2024, 12, 27, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			description: "custom weekend",
+			calendar:    Calendar{Weekend: []time.Weekday{time.Friday, time.Saturday}},
+			given:       time.Date(2024, 12, 19, 9, 30, 0, 0, time.UTC),
+			amount:      1,
+			expected:    time.Date(2024, 12, 22, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			description: "no weekend",
+			calendar:    Calendar{NoWeekend: true},
+			given:       friday,
+			amount:      1,
+			expected:    time.Date(2024, 12, 21, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			description: "no business days",
+			calendar:    Calendar{Weekend: []time.Weekday{0, 1, 2, 3, 4, 5, 6}},
+			given:       friday,
+			amount:      1,
+			expectedErr: ErrNoBusinessDays,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := tt.calendar.AddBusinessDays(tt.given, tt.amount)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
+
+func TestLoadHolidays(t *testing.T) {
+	testCases := []struct {
+		description string
+		given       string
+		expected    []Holiday
+		expectedErr error
+	}{
+		{
+			description: "yaml",
+			given: `holidays:
+  - 2024-12-24 # Christmas Eve
+  - "12-31"
+`,
+			expected: []Holiday{
+				{Name: "Christmas Eve", Year: 2024, Month: time.December, Day: 24},
+				{Month: time.December, Day: 31},
+			},
+		},
+		{
+			description: "yaml/invalid date",
+			given:       "- 24.12.2024",
+			expectedErr: errors.New("line 1: failed to parse date '24.12.2024' (use YYYY-MM-DD or MM-DD)"),
+		},
+		{
+			description: "icalendar",
+			given: "BEGIN:VCALENDAR\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"DTSTART;VALUE=DATE:20241224\r\n" +
+				"SUMMARY:Christmas\r\n" +
+				"  Eve\r\n" +
+				"END:VEVENT\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"DTSTART;VALUE=DATE:20240101\r\n" +
+				"RRULE:FREQ=YEARLY\r\n" +
+				"SUMMARY:New Year's Day\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+			expected: []Holiday{
+				{Name: "Christmas Eve", Year: 2024, Month: time.December, Day: 24},
+				{Name: "New Year's Day", Month: time.January, Day: 1},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			holidays, err := LoadHolidays(strings.NewReader(tt.given))
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, holidays, tt.expected)
+		})
+	}
+}
+
+func TestCalculatorBusinessDays(t *testing.T) {
+	calculations, err := ParseCalculations("+5B -1B")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Calculator{}.Apply(time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC), calculations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, got, time.Date(2024, 12, 26, 9, 30, 0, 0, time.UTC))
+
+	// the free function uses the default calendar as well
+	friday := time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC)
+	equal(t, Calculate(friday, Add, 1, "B"), time.Date(2024, 12, 23, 9, 30, 0, 0, time.UTC))
+	equal(t, Calculate(friday, Sub, 5, "B"), time.Date(2024, 12, 13, 9, 30, 0, 0, time.UTC))
+
+	_, err = Calculator{}.Calculate(time.Now(), Calculation{Operator: Round, Unit: "B"})
+	equal(t, errors.Is(err, ErrUnkownOperator), true)
+}
@@ -0,0 +1,25 @@
+package epoch
+
+import "time"
+
+// Clock provides the current time, e.g. for empty inputs, guessing units and relative times.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the clock of the operating system.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock is a clock which always returns the same time, e.g. to reproduce conversions.
+type FixedClock time.Time
+
+// Now returns the fixed time.
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
@@ -0,0 +1,463 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Converter converts inputs the same way as the epoch command: timestamps to formatted times
+// and formatted times to timestamps, optionally applying calculations in between.
+// Create it with NewConverter, the zero value isn't usable.
+type Converter struct {
+	location       *time.Location
+	unit           TimeUnit
+	explicitUnit   bool
+	parser         Parser
+	calculator     Calculator
+	calculations   []Calculation
+	format         string
+	relative       Relative
+	appendRelative bool
+	clock          Clock
+	notify         func(msg string)
+	stream         bool
+	sticky         *StickyParser
+	notified       map[string]bool
+}
+
+// ConverterOption configures a Converter.
+type ConverterOption func(*Converter)
+
+// NewConverter creates a converter. Without options, it behaves like the epoch command without flags:
+// timestamps are guessed (see GuessUnit) and formatted in the local timezone, formatted times
+// are converted to timestamps in seconds.
+func NewConverter(options ...ConverterOption) *Converter {
+	c := &Converter{}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// WithLocation sets the timezone of the output and of inputs without a specific timezone.
+// Formatted inputs are converted to formatted times in the location instead of timestamps.
+func WithLocation(loc *time.Location) ConverterOption {
+	return func(c *Converter) {
+		c.location = loc
+	}
+}
+
+// WithUnit sets the unit of timestamps instead of guessing it.
+// Inputs with a unit suffix, such as "1595087205ms", have to use the same unit.
+func WithUnit(unit TimeUnit) ConverterOption {
+	return func(c *Converter) {
+		c.unit, c.explicitUnit = unit, true
+	}
+}
+
+// WithLayouts sets the custom layouts, which are tried before the built-in layouts (see Parser).
+func WithLayouts(layouts ...Layout) ConverterOption {
+	return func(c *Converter) {
+		c.parser.Layouts = layouts
+	}
+}
+
+// WithLocale sets the language of names for parsing inputs and formatting with layouts (see Parser).
+// Named formats such as "rfc1123" always use English names.
+func WithLocale(locale Locale) ConverterOption {
+	return func(c *Converter) {
+		c.parser.Locale = &locale
+	}
+}
+
+// WithDateOrder sets the order of numeric dates such as "03/04/2024" (see ParseNumericDate).
+func WithDateOrder(order DateOrder) ConverterOption {
+	return func(c *Converter) {
+		c.parser.DateOrder = order
+	}
+}
+
+// WithClock sets the clock for empty inputs, guessing units and relative times. Defaults to the SystemClock.
+// Use a FixedClock to reproduce conversions.
+func WithClock(clock Clock) ConverterOption {
+	return func(c *Converter) {
+		c.clock = clock
+	}
+}
+
+// WithFormat sets the output format of times: a format name (see FormatName), "relative"
+// or a layout containing "%", "{" or a prefix such as "java:" (see ParseLayout).
+func WithFormat(format string) ConverterOption {
+	return func(c *Converter) {
+		c.format = format
+	}
+}
+
+// WithRelative configures the relative format and appended relative times.
+// A zero Reference uses the clock of the converter.
+func WithRelative(relative Relative) ConverterOption {
+	return func(c *Converter) {
+		c.relative = relative
+	}
+}
+
+// WithAppendedRelative appends the relative time to the output, e.g. "1595087205 (3 hours ago)".
+func WithAppendedRelative() ConverterOption {
+	return func(c *Converter) {
+		c.appendRelative = true
+	}
+}
+
+// WithCalculator sets the calculator for the calculations.
+func WithCalculator(calculator Calculator) ConverterOption {
+	return func(c *Converter) {
+		c.calculator = calculator
+	}
+}
+
+// WithCalculations sets the calculations applied to the input time (see ParseCalculations).
+func WithCalculations(calculations ...Calculation) ConverterOption {
+	return func(c *Converter) {
+		c.calculations = calculations
+	}
+}
+
+// WithNotify sets a function receiving notes about guessed units, e.g. "guessed unit: seconds".
+func WithNotify(notify func(msg string)) ConverterOption {
+	return func(c *Converter) {
+		c.notify = notify
+	}
+}
+
+// WithStream configures the converter for streams of inputs in the same format, e.g. log lines.
+// The layout of the last input is tried first (see StickyParser) and each note, such as the guessed unit,
+// is only reported once. The converter must not be used concurrently.
+func WithStream() ConverterOption {
+	return func(c *Converter) {
+		c.stream = true
+	}
+}
+
+// Location returns the location of the converter, the local timezone when not set.
+func (c *Converter) Location() *time.Location {
+	if c.location == nil {
+		return time.Local
+	}
+	return c.location
+}
+
+// Now returns the current time of the clock.
+func (c *Converter) Now() time.Time {
+	return c.now()
+}
+
+// Parser returns the parser of formatted inputs.
+func (c *Converter) Parser() Parser {
+	return c.parser
+}
+
+// Calculator returns the calculator of the converter.
+func (c *Converter) Calculator() Calculator {
+	return c.calculator
+}
+
+// parsedInput is an input converted to the time type.
+type parsedInput struct {
+	time time.Time
+	// timestamp reports whether the input was a timestamp
+	timestamp bool
+	// unit is the unit of timestamps, if explicit
+	unit         TimeUnit
+	explicitUnit bool
+}
+
+// Convert converts a timestamp to a formatted time or a formatted time to a timestamp and applies the calculations.
+//
+// Timestamps are formatted in the location, or as timestamps again when there are calculations.
+// Formatted times are converted to timestamps, or to formatted times when a location or format is set.
+// The time of the clock is used for empty inputs.
+func (c *Converter) Convert(s string) (string, error) {
+	in, err := c.read(s)
+	if err != nil {
+		return "", err
+	}
+
+	if in.timestamp {
+		if len(c.calculations) == 0 {
+			return c.formatTime(in.time)
+		}
+
+		t, err := c.calculator.Apply(in.time, c.calculations)
+		if err != nil {
+			return "", err
+		}
+		// the guessed unit was already reported when reading the input
+		return c.timestamp(t, in.unit, in.explicitUnit, false)
+	}
+
+	return c.ConvertTime(in.time)
+}
+
+// ConvertTime converts the time of a formatted input the same way as Convert, e.g. a time found by Parser.Extract.
+// The calculations are applied and the time is converted to a timestamp, or to a formatted time when a location or format is set.
+func (c *Converter) ConvertTime(t time.Time) (string, error) {
+	// a timezone and/or format was specified, convert the formatted input to another timezone and/or format
+	if c.location != nil || c.format != "" {
+		if c.explicitUnit {
+			return "", fmt.Errorf("can't use unit together with timezone or format on a formatted string (omit the unit)")
+		}
+
+		t, err := c.calculator.Apply(t.In(c.Location()), c.calculations)
+		if err != nil {
+			return "", err
+		}
+		return c.formatTime(t)
+	}
+
+	t, err := c.calculator.Apply(t, c.calculations)
+	if err != nil {
+		return "", err
+	}
+	return c.timestamp(t, c.unit, c.explicitUnit, true)
+}
+
+// ParseInput converts a timestamp or a formatted time to the time type in the location.
+// The time of the clock is used for empty inputs. Inputs matching a custom layout are always formatted times.
+func (c *Converter) ParseInput(s string) (time.Time, error) {
+	in, err := c.read(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return in.time.In(c.Location()), nil
+}
+
+// Calculate applies the calculations to the time with the calculator, e.g. to the start of a sequence.
+func (c *Converter) Calculate(t time.Time) (time.Time, error) {
+	return c.calculator.Apply(t, c.calculations)
+}
+
+// Render outputs the time as timestamp when a unit is set, otherwise, formatted with the output format.
+func (c *Converter) Render(t time.Time) (string, error) {
+	if c.explicitUnit {
+		if c.format != "" {
+			return "", fmt.Errorf("can't use unit and format together")
+		}
+		return c.timestamp(t, c.unit, true, false)
+	}
+	return c.formatTime(t)
+}
+
+// read parses the input, timestamps are returned in the location.
+func (c *Converter) read(s string) (parsedInput, error) {
+	if s == "" {
+		return parsedInput{time: c.now()}, nil
+	}
+
+	// custom layouts might match numbers, e.g. "20060102"
+	if t, ok := c.parseLayouts(s); ok {
+		return parsedInput{time: t}, nil
+	}
+
+	s, unit, explicit, err := c.cutUnit(s)
+	if err != nil {
+		return parsedInput{}, err
+	}
+
+	// if the input can be parsed as a number, we assume it's an epoch timestamp
+	if i, ok, err := parseTimestampNumber(s); ok {
+		if err != nil {
+			return parsedInput{}, err
+		}
+		if !explicit {
+			unit = GuessUnit(i, c.now())
+			c.notifyf("guessed unit: %v", unitNames[unit])
+		}
+
+		t, err := ParseTimestamp(i, unit)
+		if err != nil {
+			return parsedInput{}, fmt.Errorf("failed to convert from timestamp: %w", err)
+		}
+		return parsedInput{time: t.In(c.Location()), timestamp: true, unit: unit, explicitUnit: explicit}, nil
+	}
+
+	t, err := c.parseBuiltin(s)
+	if err != nil {
+		return parsedInput{}, err
+	}
+	return parsedInput{time: t}, nil
+}
+
+// parseBuiltin parses the input with the layouts of the parser except the custom layouts, which didn't match.
+// Streams remember the last layout.
+func (c *Converter) parseBuiltin(s string) (time.Time, error) {
+	builtin := c.parser
+	builtin.Layouts = nil
+
+	var (
+		t   time.Time
+		err error
+	)
+	if c.stream {
+		if c.sticky == nil {
+			c.sticky = &StickyParser{Parser: builtin}
+		}
+		t, _, err = c.sticky.Parse(s, c.Location())
+	} else {
+		t, _, err = builtin.Parse(s, c.Location())
+	}
+
+	// the custom layouts are part of the diagnosis of parse errors
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return time.Time{}, c.parser.parseError(s, c.Location(), ErrParseFormatted)
+	}
+	return t, err
+}
+
+// parseTimestampNumber parses integers without losing the precision of nanoseconds, other numbers are truncated.
+// It reports false when the input isn't a number.
+func parseTimestampNumber(s string) (int64, bool, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, true, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, false, nil
+	}
+	if err != nil || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, true, fmt.Errorf("timestamp %v %w", s, ErrOutOfRange)
+	}
+	return int64(f), true, nil
+}
+
+// cutUnit removes a unit suffix from the input, e.g. "1234567890s" is "1234567890" in seconds.
+func (c *Converter) cutUnit(s string) (string, TimeUnit, bool, error) {
+	// keep "s" as last element in slice, otherwise,
+	// it will match all other units as they end with an "s", too.
+	for _, suffix := range []string{"ns", "us", "ms", "s"} {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+
+		// check if remaining input is an integer, if not
+		// it might be a time zone ending with the suffix.
+		// (I'm currently not aware of any, but let's be sure)
+		trimmed := strings.TrimSuffix(s, suffix)
+		if _, err := strconv.ParseInt(trimmed, 10, 64); err != nil {
+			continue
+		}
+
+		unit, _ := ParseUnit(suffix)
+		if c.explicitUnit && c.unit != unit {
+			return "", 0, false, fmt.Errorf("mismatch between unit (%v) and input unit (%v)", unitNames[c.unit], unitNames[unit])
+		}
+		return trimmed, unit, true, nil
+	}
+	return s, c.unit, c.explicitUnit, nil
+}
+
+// parseLayouts returns the time of the first custom layout which can parse the input.
+func (c *Converter) parseLayouts(s string) (time.Time, bool) {
+	for _, layout := range c.parser.Layouts {
+		if c.parser.Locale != nil {
+			layout = layout.WithLocale(*c.parser.Locale)
+		}
+		if t, err := layout.Parse(s, c.Location()); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// timestamp outputs the time as timestamp, in seconds when the unit isn't explicit.
+func (c *Converter) timestamp(t time.Time, unit TimeUnit, explicit, notify bool) (string, error) {
+	if !explicit {
+		unit = UnitSeconds
+		if notify {
+			c.notifyf("using seconds as unit")
+		}
+	}
+
+	timestamp, err := ToTimestamp(t, unit)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert timestamp: %w", err)
+	}
+	return c.withRelative(strconv.FormatInt(timestamp, 10), t)
+}
+
+// formatTime outputs the time with a named format (e.g. 'rfc3339' or 'relative') or a layout (e.g. '%Y-%m-%d' or 'java:yyyy-MM-dd', see ParseLayout).
+// The locale is only used for layouts, named formats always use English names.
+func (c *Converter) formatTime(t time.Time) (string, error) {
+	format, err := FormatName(c.format)
+	if err == nil {
+		if format == FormatRelative {
+			return c.relativeTo().Format(t)
+		}
+		return c.withRelative(t.Format(format), t)
+	}
+
+	// only use layouts which can't be confused with misspelled format names
+	if !strings.ContainsAny(c.format, "%{:") {
+		return "", err
+	}
+
+	layout, err := ParseLayout(c.format)
+	if err != nil {
+		return "", err
+	}
+	if c.parser.Locale != nil {
+		layout = layout.WithLocale(*c.parser.Locale)
+	}
+	return c.withRelative(layout.Format(t), t)
+}
+
+// withRelative appends the relative time when requested.
+func (c *Converter) withRelative(s string, t time.Time) (string, error) {
+	if !c.appendRelative {
+		return s, nil
+	}
+	relative, err := c.relativeTo().Format(t)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v (%v)", s, relative), nil
+}
+
+// relativeTo returns the relative configuration with the clock of the converter.
+func (c *Converter) relativeTo() Relative {
+	relative := c.relative
+	if relative.Clock == nil {
+		relative.Clock = c.clock
+	}
+	return relative
+}
+
+func (c *Converter) now() time.Time {
+	if c.clock == nil {
+		return SystemClock.Now()
+	}
+	return c.clock.Now()
+}
+
+func (c *Converter) notifyf(format string, args ...any) {
+	if c.notify == nil {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	if c.stream {
+		if c.notified[msg] {
+			return
+		}
+		if c.notified == nil {
+			c.notified = map[string]bool{}
+		}
+		c.notified[msg] = true
+	}
+	c.notify(msg)
+}
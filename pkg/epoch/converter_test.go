@@ -0,0 +1,233 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConverter(t *testing.T) {
+	type args struct {
+		input        string
+		now          string
+		unit         string
+		format       string
+		inputFormats []string
+		locale       string
+		dateOrder    DateOrder
+		relative     *Relative
+		tz           string
+		calc         string
+		calculator   Calculator
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		// Remember that timezone "Local" can't be used as the CI might have a different timezone!
+		{name: "empty input", args: args{now: "2020-07-18 17:46:45.215239 +0200 CEST"}, want: "1595087205"},
+		{name: "empty input/utc", args: args{now: "2020-07-18 17:46:45.215239 +0200 CEST", tz: "UTC"}, want: "2020-07-18 15:46:45.215239 +0000 UTC"},
+
+		{name: "empty input/relative", args: args{now: "2020-07-18 17:46:45 +0200 CEST", format: "relative", relative: &Relative{}}, want: "now"},
+		{name: "timestamp/guess/clock", args: args{input: "1595087205", now: "1970-01-19 11:04:47 +0000 UTC", tz: "UTC"}, want: "1970-01-19 11:04:47.205 +0000 UTC"},
+		{name: "timestamp/relative/clock", args: args{input: "1595087205", now: "2020-07-18 18:00:00 +0000 UTC", format: "relative", relative: &Relative{}}, want: "2 hours ago"},
+
+		{name: "timedate", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST"}, want: "1595087205"},
+		{name: "timedate/unit", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", unit: "ms"}, want: "1595087205215"},
+		{name: "timedate/timezone", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", tz: "MST"}, want: "2020-07-18 08:46:45.215239 -0700 MST"},
+		{name: "timedate/timezone/format", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", format: "unix", tz: "UTC"}, want: "Sat Jul 18 15:46:45 UTC 2020"},
+		{name: "timedate/timezone/unit/FAIL", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", unit: "ms", format: "unix", tz: "UTC"}, wantErr: true},
+
+		{name: "timestamp/timezone/unitsuffix", args: args{input: "1595087205us", tz: "Europe/Berlin"}, want: "1970-01-01 01:26:35.087205 +0100 CET"},
+		{name: "timestamp/timezone/unit", args: args{input: "1595087205", tz: "Europe/Berlin", unit: "ms"}, want: "1970-01-19 12:04:47.205 +0100 CET"},
+		{name: "timestamp/timezone", args: args{input: "1595087205", tz: "UTC"}, want: "2020-07-18 15:46:45 +0000 UTC"},
+		{name: "timestamp/out of range/FAIL", args: args{input: "1e30", tz: "UTC"}, wantErr: true},
+		{name: "timestamp/unit/out of range/FAIL", args: args{input: "9223372036000000000", calc: "+1h", unit: "ns"}, wantErr: true},
+		{name: "timestamp/unit/nanosecond precision", args: args{input: "1704067200123456789", unit: "ns", format: "rfc3339nano", tz: "UTC"}, want: "2024-01-01T00:00:00.123456789Z"},
+		{name: "timestamp/fraction", args: args{input: "1595087205.9", unit: "s", tz: "UTC"}, want: "2020-07-18 15:46:45 +0000 UTC"},
+		{name: "timestamp/int overflow/FAIL", args: args{input: "9223372036854775808", tz: "UTC"}, wantErr: true},
+		{name: "timestamp/timezone/format", args: args{input: "1595087205", format: "ruby", tz: "UTC"}, want: "Sat Jul 18 15:46:45 +0000 2020"},
+
+		// strftime
+		{name: "strftime timestamp/format", args: args{input: "1595087205", format: "%Y-%m-%dT%H:%M:%S%:z %j", tz: "UTC"}, want: "2020-07-18T15:46:45+00:00 200"},
+		{name: "strftime timedate/format", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", format: "%-d.%-m.%Y %3N", tz: "Europe/Berlin"}, want: "18.7.2020 215"},
+		{name: "strftime timedate/format/FAIL", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", format: "%Q", tz: "UTC"}, wantErr: true},
+		{name: "strptime timedate", args: args{input: "18.07.2020 17:46", inputFormats: []string{"%d.%m.%Y %H:%M"}, tz: "Europe/Berlin"}, want: "2020-07-18 17:46:00 +0200 CEST"},
+		{name: "strptime timedate/unit", args: args{input: "18.07.2020 17:46 +0200", inputFormats: []string{"%d.%m.%Y %H:%M %z"}, unit: "s"}, want: "1595087160"},
+		{name: "strptime numeric/format", args: args{input: "20200718", inputFormats: []string{"%Y%m%d"}, format: "%G-W%V-%u", tz: "UTC"}, want: "2020-W29-6"},
+		{name: "strptime empty input", args: args{now: "2020-07-18 17:46:45.215239 +0200 CEST", inputFormats: []string{"%d.%m.%Y"}, tz: "UTC"}, want: "2020-07-18 15:46:45.215239 +0000 UTC"},
+		{name: "java timedate/format", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", format: "java:yyyy-MM-dd'T'HH:mm:ss.SSSXXX", tz: "UTC"}, want: "2020-07-18T15:46:45.215Z"},
+		{name: "dotnet timestamp/format", args: args{input: "1595087205", format: "dotnet:dddd, dd MMMM yyyy HH:mm", tz: "UTC"}, want: "Saturday, 18 July 2020 15:46"},
+		{name: "java timedate/format/FAIL", args: args{input: "1595087205", format: "java:H:mm", tz: "UTC"}, wantErr: true},
+		{name: "moment input", args: args{input: "2020-07-18 17:46", inputFormats: []string{"moment:YYYY-MM-DD HH:mm"}, tz: "Europe/Berlin"}, want: "2020-07-18 17:46:00 +0200 CEST"},
+		{name: "go input/format", args: args{input: "18 Jul 20 17:46 +0200", inputFormats: []string{"go:02 Jan 06 15:04 -0700"}, format: "go:2006/01/02", tz: "UTC"}, want: "2020/07/18"},
+		{name: "simple timestamp/format", args: args{input: "1595087205", format: "simple:{GGGG}-W{WW} Q{Q} {MMMM} {Do}", tz: "UTC"}, want: "2020-W29 Q3 July 18th"},
+		{name: "simple timestamp/format/FAIL", args: args{input: "1595087205", format: "simple:{YYYY}-{MMMMM}", tz: "UTC"}, wantErr: true},
+		{name: "simple input", args: args{input: "18.07.2020 17:46", inputFormats: []string{"simple:{DD}.{MM}.{YYYY} {HH}:{mm}"}, tz: "UTC"}, want: "2020-07-18 17:46:00 +0000 UTC"},
+		{name: "simple input/FAIL", args: args{input: "2020-W29", inputFormats: []string{"simple:{GGGG}-W{WW}"}, tz: "UTC"}, wantErr: true},
+		{name: "locale input", args: args{input: "25. Januar 2019", locale: "de", tz: "UTC"}, want: "2019-01-25 00:00:00 +0000 UTC"},
+		{name: "locale input/date order", args: args{input: "03/04/2020", locale: "fr", format: "rfc3339", tz: "UTC"}, want: "2020-04-03T00:00:00Z"},
+		{name: "locale input/format", args: args{input: "mardi 3 mars 2020", locale: "fr", format: "%A %-d %B %Y", tz: "UTC"}, want: "mardi 3 mars 2020"},
+		{name: "locale timestamp/format", args: args{input: "1595087205", locale: "de", format: "go:Monday, 2. January 2006", tz: "UTC"}, want: "Samstag, 18. Juli 2020"},
+		{name: "locale named format", args: args{input: "1595087205", locale: "de", format: "rfc1123", tz: "UTC"}, want: "Sat, 18 Jul 2020 15:46:45 UTC"},
+		{name: "locale custom input", args: args{input: "sábado 18 julio 2020", locale: "es", inputFormats: []string{"%A %d %B %Y"}, tz: "UTC"}, want: "2020-07-18 00:00:00 +0000 UTC"},
+		{name: "relative format", args: args{input: "1595087205", format: "relative", relative: &Relative{Reference: time.Date(2020, 7, 18, 18, 0, 0, 0, time.UTC)}}, want: "2 hours ago"},
+		{name: "relative format/precision", args: args{input: "2020-07-21 12:00:00 +0000 UTC", format: "relative", relative: &Relative{Reference: time.Date(2020, 7, 18, 15, 46, 45, 0, time.UTC), Precision: 2, Compact: true}}, want: "in 2d 20h"},
+		{name: "relative appended", args: args{input: "1595087205", format: "rfc3339", relative: &Relative{Reference: time.Date(2020, 7, 18, 18, 0, 0, 0, time.UTC)}, tz: "UTC"}, want: "2020-07-18T15:46:45Z (2 hours ago)"},
+		{name: "relative appended/timestamp", args: args{input: "2020-07-18 17:46:45 +0200 CEST", relative: &Relative{Reference: time.Date(2020, 7, 18, 15, 46, 0, 0, time.UTC)}}, want: "1595087205 (in 45 seconds)"},
+		{name: "relative granularity/FAIL", args: args{input: "1595087205", format: "relative", relative: &Relative{Granularity: "x"}}, wantErr: true},
+		{name: "numeric date", args: args{input: "25/01/2019 21:51", tz: "UTC"}, want: "2019-01-25 21:51:00 +0000 UTC"},
+		{name: "numeric date/ambiguous/FAIL", args: args{input: "03/04/2024", tz: "UTC"}, wantErr: true},
+		{name: "numeric date/order", args: args{input: "03/04/2024", dateOrder: DateOrderDMY, format: "rfc3339", tz: "UTC"}, want: "2024-04-03T00:00:00Z"},
+		{name: "layouts/first match", args: args{input: "18/07/2020 17:46", inputFormats: []string{"%d.%m.%Y %H:%M", "02/01/2006 15:04"}, tz: "UTC"}, want: "2020-07-18 17:46:00 +0000 UTC"},
+		{name: "layouts/builtin fallback", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", inputFormats: []string{"%d.%m.%Y %H:%M"}}, want: "1595087205"},
+		{name: "layouts/numeric input", args: args{input: "20200718", inputFormats: []string{"20060102"}, tz: "UTC"}, want: "2020-07-18 00:00:00 +0000 UTC"},
+		{name: "layouts/numeric input/timestamp fallback", args: args{input: "1595087205", inputFormats: []string{"20060102"}, tz: "UTC"}, want: "2020-07-18 15:46:45 +0000 UTC"},
+		{name: "strptime mismatch/FAIL", args: args{input: "July 18, 2020", inputFormats: []string{"%d.%m.%Y"}, tz: "UTC"}, wantErr: true},
+
+		// arithmetics
+		{name: "arithmetics empty input", args: args{input: "", calc: "+1h", now: "2020-07-18 17:46:45.215239 +0200 CEST"}, want: "1595090805"},
+		{name: "arithmetics empty input/utc", args: args{input: "", calc: "+1h", now: "2020-07-18 17:46:45.215239 +0200 CEST", tz: "UTC"}, want: "2020-07-18 16:46:45.215239 +0000 UTC"},
+
+		{name: "arithmetics timedate/timezone/add/no_tz", args: args{input: "2020-07-18 17:46:45.215239", calc: "+1h", tz: "MST"}, want: "2020-07-18 18:46:45.215239 -0700 MST"},
+		{name: "arithmetics timedate/timezone/add", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "+1h", tz: "MST"}, want: "2020-07-18 09:46:45.215239 -0700 MST"},
+		{name: "arithmetics timedate/timezone/sub", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "-1h", tz: "MST"}, want: "2020-07-18 07:46:45.215239 -0700 MST"},
+		{name: "arithmetics timedate/timezone/multiple", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "-30m +1h -5D +3W -6M +2Y", tz: "MST"}, want: "2022-02-03 09:16:45.215239 -0700 MST"},
+		{name: "arithmetics timedate/timezone/format", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "+1h", format: "unix", tz: "UTC"}, want: "Sat Jul 18 16:46:45 UTC 2020"},
+		{name: "arithmetics timedate/timezone/unit/FAIL", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "+1h", unit: "ms", format: "unix", tz: "UTC"}, wantErr: true},
+
+		{name: "arithmetics timestamp/timezone/unitsuffix", args: args{input: "1595087205us", calc: "+1h", tz: "MST"}, want: "5195087205"},
+		{name: "arithmetics timestamp/timezone/unit", args: args{input: "1595087205", calc: "+1h", tz: "MST", unit: "ms"}, want: "1598687205"},
+		{name: "arithmetics timestamp/timezone/multiple", args: args{input: "1595087205", calc: "-30m +1h -5D +3W -6M +2Y", tz: "MST"}, want: "1643905005"},
+
+		// boundaries
+		{name: "boundaries timedate/timezone/startof", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "/D", tz: "UTC"}, want: "2020-07-18 00:00:00 +0000 UTC"},
+		{name: "boundaries timedate/timezone/startof/named", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "startof:W", tz: "UTC"}, want: "2020-07-13 00:00:00 +0000 UTC"},
+		{name: "boundaries timedate/timezone/endof", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "endof:M", tz: "Europe/Berlin"}, want: "2020-07-31 23:59:59.999999999 +0200 CEST"},
+		{name: "boundaries timedate/timezone/round", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "round:h", tz: "UTC"}, want: "2020-07-18 16:00:00 +0000 UTC"},
+		{name: "boundaries timedate/timezone/combined", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "-1D /D", tz: "UTC"}, want: "2020-07-17 00:00:00 +0000 UTC"},
+		{name: "boundaries timestamp/timezone", args: args{input: "1595087205", calc: "/Q", tz: "UTC"}, want: "1593561600"},
+		{name: "boundaries unknown unit/FAIL", args: args{input: "1595087205", calc: "/X", tz: "UTC"}, wantErr: true},
+
+		// set
+		{name: "set timedate/timezone", args: args{input: "2020-07-18 17:46:45.215239 +0200 CEST", calc: "=9h =0m =0s =0ns", tz: "UTC"}, want: "2020-07-18 09:00:00 +0000 UTC"},
+		{name: "set timedate/timezone/clamp", args: args{input: "2020-01-31 12:00:00 +0000 UTC", calc: "=2M", tz: "UTC"}, want: "2020-02-29 12:00:00 +0000 UTC"},
+		{name: "set timestamp/timezone", args: args{input: "1595087205", calc: "=1D", tz: "UTC"}, want: "1593618405"},
+		{name: "set out of range/FAIL", args: args{input: "1595087205", calc: "=60m", tz: "UTC"}, wantErr: true},
+
+		// overflow
+		{name: "overflow timedate/timezone/normalize", args: args{input: "2024-01-31 12:00:00 +0000 UTC", calc: "+1M", tz: "UTC"}, want: "2024-03-02 12:00:00 +0000 UTC"},
+		{name: "overflow timedate/timezone/clamp", args: args{input: "2024-01-31 12:00:00 +0000 UTC", calc: "+1M", calculator: Calculator{Overflow: OverflowClamp}, tz: "UTC"}, want: "2024-02-29 12:00:00 +0000 UTC"},
+		{name: "overflow timedate/timezone/error/FAIL", args: args{input: "2024-01-31 12:00:00 +0000 UTC", calc: "+1M", calculator: Calculator{Overflow: OverflowError}, tz: "UTC"}, wantErr: true},
+
+		// dst
+		{name: "dst timedate/timezone/hours", args: args{input: "2020-10-24 12:00:00 +0200 CEST", calc: "+24h", tz: "Europe/Berlin"}, want: "2020-10-25 11:00:00 +0100 CET"},
+		{name: "dst timedate/timezone/hours/wall", args: args{input: "2020-10-24 12:00:00 +0200 CEST", calc: "+24h", calculator: Calculator{TimeMode: ModeWallClock}, tz: "Europe/Berlin"}, want: "2020-10-25 12:00:00 +0100 CET"},
+		{name: "dst timedate/timezone/days", args: args{input: "2020-10-24 12:00:00 +0200 CEST", calc: "+1D", tz: "Europe/Berlin"}, want: "2020-10-25 12:00:00 +0100 CET"},
+		{name: "dst timedate/timezone/days/elapsed", args: args{input: "2020-10-24 12:00:00 +0200 CEST", calc: "+1D", calculator: Calculator{DateMode: ModeElapsed}, tz: "Europe/Berlin"}, want: "2020-10-25 11:00:00 +0100 CET"},
+
+		// business days
+		{name: "business days timedate/timezone", args: args{input: "2020-07-17 17:46:45 +0000 UTC", calc: "+1B", tz: "UTC"}, want: "2020-07-20 17:46:45 +0000 UTC"},
+		{name: "business days timedate/timezone/weekend", args: args{input: "2020-07-17 17:46:45 +0000 UTC", calc: "+1B", calculator: Calculator{Calendar: &Calendar{Weekend: []time.Weekday{time.Friday, time.Saturday}}}, tz: "UTC"}, want: "2020-07-19 17:46:45 +0000 UTC"},
+		{name: "business days timedate/timezone/holidays", args: args{input: "2020-07-02 12:00:00 +0000 UTC", calc: "+2B", calculator: Calculator{Calendar: &Calendar{Holidays: []Holiday{{Month: time.July, Day: 3}}}}, tz: "UTC"}, want: "2020-07-07 12:00:00 +0000 UTC"},
+		{name: "business days truncate/FAIL", args: args{input: "1595087205", calc: "/B", tz: "UTC"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := []ConverterOption{
+				WithFormat(tt.args.format),
+				WithDateOrder(tt.args.dateOrder),
+				WithCalculator(tt.args.calculator),
+			}
+			if tt.args.now != "" {
+				now, _, err := ParseFormatted(tt.args.now, time.UTC)
+				equal(t, err, nil)
+				options = append(options, WithClock(FixedClock(now)))
+			}
+			if tt.args.unit != "" {
+				unit, err := ParseUnit(tt.args.unit)
+				equal(t, err, nil)
+				options = append(options, WithUnit(unit))
+			}
+			if tt.args.tz != "" {
+				loc, err := time.LoadLocation(tt.args.tz)
+				equal(t, err, nil)
+				options = append(options, WithLocation(loc))
+			}
+			var layouts []Layout
+			for _, format := range tt.args.inputFormats {
+				layout, err := ParseLayout(format)
+				equal(t, err, nil)
+				layouts = append(layouts, layout)
+			}
+			options = append(options, WithLayouts(layouts...))
+			if tt.args.locale != "" {
+				locale, err := LookupLocale(tt.args.locale)
+				equal(t, err, nil)
+				options = append(options, WithLocale(locale))
+			}
+			if tt.args.relative != nil {
+				options = append(options, WithRelative(*tt.args.relative))
+				if tt.args.format != FormatRelative {
+					options = append(options, WithAppendedRelative())
+				}
+			}
+
+			calculations, err := ParseCalculations(tt.args.calc)
+			if err == nil {
+				options = append(options, WithCalculations(calculations...))
+			}
+
+			var got string
+			if err == nil {
+				got, err = NewConverter(options...).Convert(tt.args.input)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverterParseError(t *testing.T) {
+	layout, err := ParseLayout("%d.%m.%Y")
+	equal(t, err, nil)
+
+	_, err = NewConverter(WithLayouts(layout)).Convert("July 18, 2020")
+	var parseErr *ParseError
+	equal(t, errors.As(err, &parseErr), true)
+	// the custom layouts are only tried once, but still part of the diagnosis
+	equal(t, parseErr.Layouts()[0], "%d.%m.%Y")
+}
+
+func TestConverterStream(t *testing.T) {
+	var notes []string
+	converter := NewConverter(
+		WithStream(),
+		WithLocation(time.UTC),
+		WithFormat("rfc3339"),
+		WithClock(FixedClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+		WithNotify(func(msg string) { notes = append(notes, msg) }),
+	)
+
+	var got []string
+	for _, input := range []string{"1704067200", "1704067201", "25/01/2024", "03/04/2024"} {
+		output, err := converter.Convert(input)
+		equal(t, err, nil)
+		got = append(got, output)
+	}
+
+	// the ambiguous date is parsed in the order of the last date
+	equal(t, got, []string{"2024-01-01T00:00:00Z", "2024-01-01T00:00:01Z", "2024-01-25T00:00:00Z", "2024-04-03T00:00:00Z"})
+	equal(t, notes, []string{"guessed unit: seconds"})
+
+	_, err := converter.Convert("yesterday")
+	var parseErr *ParseError
+	equal(t, errors.As(err, &parseErr), true)
+}
@@ -0,0 +1,389 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a series of recurring times, such as a cron expression or an RRULE.
+type Schedule interface {
+	// Next returns the first occurrence after t, false when there is none.
+	Next(t time.Time) (time.Time, bool)
+	// Prev returns the last occurrence before t, false when there is none.
+	Prev(t time.Time) (time.Time, bool)
+}
+
+// ErrNoOccurrences is returned when a schedule doesn't have any occurrence after (or before) a time,
+// e.g. for February 30.
+var ErrNoOccurrences = errors.New("no occurrences")
+
+// iterableSchedule is implemented by schedules which iterate their occurrences faster than repeated calls of Next or Prev.
+type iterableSchedule interface {
+	AllAfter(t time.Time) iter.Seq[time.Time]
+	AllBefore(t time.Time) iter.Seq[time.Time]
+	// Err returns the error which ended the last iteration early.
+	Err() error
+}
+
+// Occurrences returns up to count occurrences of the schedule after t,
+// or before t in descending order when count is negative.
+// It returns ErrNoOccurrences when there isn't any, or the error which ended the iteration of the schedule.
+func Occurrences(s Schedule, t time.Time, count int) ([]time.Time, error) {
+	var occurrences []time.Time
+
+	backward := count < 0
+	if backward {
+		count = -count
+	}
+
+	for occurrence := range walk(s, t, backward) {
+		if len(occurrences) >= count {
+			break
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	if seq, ok := s.(iterableSchedule); ok && seq.Err() != nil {
+		return nil, seq.Err()
+	}
+	if len(occurrences) == 0 && count > 0 {
+		return nil, ErrNoOccurrences
+	}
+	return occurrences, nil
+}
+
+// walk returns the occurrences after t, or before t in descending order when backward.
+func walk(s Schedule, t time.Time, backward bool) iter.Seq[time.Time] {
+	if seq, ok := s.(iterableSchedule); ok {
+		if backward {
+			return seq.AllBefore(t)
+		}
+		return seq.AllAfter(t)
+	}
+
+	next := s.Next
+	if backward {
+		next = s.Prev
+	}
+
+	return func(yield func(time.Time) bool) {
+		for t := t; ; {
+			var ok bool
+			t, ok = next(t)
+			if !ok || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// ErrParseCron is returned when a cron expression is invalid.
+var ErrParseCron = errors.New("failed to parse cron expression")
+
+// calendarSpec matches wall clock times by sets of allowed components.
+// Each set is a bitmask, e.g. bit 5 of minutes is set when minute 5 matches.
+type calendarSpec struct {
+	seconds  uint64 // 0-59
+	minutes  uint64 // 0-59
+	hours    uint64 // 0-23
+	days     uint64 // 1-31
+	months   uint64 // 1-12
+	weekdays uint64 // 0-6, Sunday is 0
+
+	// dayOr matches a day when either the day of the month or the weekday matches,
+	// as cron does when both fields are restricted.
+	dayOr bool
+
+	// daysFromEnd counts the days from the end of the month, bit 1 is the last day (systemd's "~").
+	daysFromEnd bool
+	// years are the allowed years, all years when nil.
+	years map[int]bool
+
+	loc *time.Location
+}
+
+// searchYears limits the search for the next or previous occurrence.
+// Some expressions never match (e.g. February 30) or only match rarely.
+const searchYears = 100
+
+func (c *calendarSpec) matchesDay(date time.Time) bool {
+	if c.months&(1<<uint(date.Month())) == 0 {
+		return false
+	}
+	if c.years != nil && !c.years[date.Year()] {
+		return false
+	}
+
+	day := date.Day()
+	if c.daysFromEnd {
+		day = time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day() - day + 1
+	}
+	dayMatch := c.days&(1<<uint(day)) != 0
+	weekdayMatch := c.weekdays&(1<<uint(date.Weekday())) != 0
+
+	if c.dayOr {
+		return dayMatch || weekdayMatch
+	}
+	return dayMatch && weekdayMatch
+}
+
+// Next returns the first matching time after t.
+// Times skipped by a DST transition don't match, times repeated by a DST transition only match once.
+func (c *calendarSpec) Next(t time.Time) (time.Time, bool) {
+	return c.search(t, 1)
+}
+
+// Prev returns the last matching time before t.
+// Times skipped by a DST transition don't match, times repeated by a DST transition only match once.
+func (c *calendarSpec) Prev(t time.Time) (time.Time, bool) {
+	return c.search(t, -1)
+}
+
+func (c *calendarSpec) search(t time.Time, direction int) (time.Time, bool) {
+	t = t.In(c.loc)
+	year, month, day := t.Date()
+	startHour, startMin, startSec := t.Clock()
+
+	for i := 0; i <= searchYears*366; i++ {
+		date := time.Date(year, month, day+i*direction, 0, 0, 0, 0, time.UTC)
+
+		if !c.matchesDay(date) {
+			continue
+		}
+
+		for _, hour := range ordered(c.hours, 24, direction) {
+			if i == 0 && (hour-startHour)*direction < 0 {
+				continue
+			}
+			for _, min := range ordered(c.minutes, 60, direction) {
+				if i == 0 && hour == startHour && (min-startMin)*direction < 0 {
+					continue
+				}
+				for _, sec := range ordered(c.seconds, 60, direction) {
+					if i == 0 && hour == startHour && min == startMin && (sec-startSec)*direction < 0 {
+						continue
+					}
+
+					candidate := earliest(time.Date(date.Year(), date.Month(), date.Day(), hour, min, sec, 0, c.loc))
+					if (direction > 0 && !candidate.After(t)) || (direction < 0 && !candidate.Before(t)) {
+						continue
+					}
+					// skipped by a DST transition, time.Date normalized it to another wall clock time
+					if candidate.Hour() != hour || candidate.Minute() != min || candidate.Day() != date.Day() {
+						continue
+					}
+					return candidate, true
+				}
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// earliest returns the first instant with the same wall clock time as t.
+// It only differs from t when the wall clock time is repeated because the clocks were turned back.
+func earliest(t time.Time) time.Time {
+	_, offset := t.Zone()
+	_, offsetBefore := t.Add(-24 * time.Hour).Zone()
+
+	if offsetBefore <= offset {
+		return t
+	}
+
+	alt := t.Add(-time.Duration(offsetBefore-offset) * time.Second)
+	if alt.Hour() != t.Hour() || alt.Minute() != t.Minute() || alt.Second() != t.Second() || alt.Day() != t.Day() {
+		return t
+	}
+	return alt
+}
+
+// ordered returns the set bits of the mask below max in ascending or descending (negative direction) order.
+func ordered(mask uint64, max, direction int) []int {
+	values := make([]int, 0, bits.OnesCount64(mask))
+	for i := range max {
+		if mask&(1<<uint(i)) != 0 {
+			values = append(values, i)
+		}
+	}
+	if direction < 0 {
+		for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+	return values
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	cronMonthNames   = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronWeekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// ParseCron parses a standard cron expression with 5 fields (minute, hour, day of month, month, day of week)
+// or 6 fields (with a leading second field). The fields support lists ("1,15"), ranges ("1-5"),
+// steps ("*/15", "10-40/10"), names ("JAN", "MON") and "?" as an alias for "*". Sunday is 0 or 7.
+// Macros such as "@daily" or "@hourly" are supported as well.
+//
+// The expression is evaluated in the given location (Local when nil), unless it starts with "CRON_TZ=<zone>" or "TZ=<zone>".
+// Like in cron, when both the day of the month and the day of the week are restricted, either has to match.
+// Times skipped by a DST transition don't match, times repeated by a DST transition only match once.
+func ParseCron(expr string, loc *time.Location) (Schedule, error) {
+	fields := strings.Fields(expr)
+
+	if len(fields) > 0 {
+		for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+			name, found := strings.CutPrefix(fields[0], prefix)
+			if !found {
+				continue
+			}
+			var err error
+			loc, err = time.LoadLocation(name)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrParseCron, err)
+			}
+			fields = fields[1:]
+			break
+		}
+	}
+
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		macro, ok := cronMacros[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown macro '%v'", ErrParseCron, fields[0])
+		}
+		fields = strings.Fields(macro)
+	}
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: expected 5 or 6 fields, got %v", ErrParseCron, len(fields))
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	spec := &calendarSpec{loc: loc}
+
+	var err error
+	if spec.seconds, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("%w: second: %v", ErrParseCron, err)
+	}
+	if spec.minutes, err = parseCronField(fields[1], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("%w: minute: %v", ErrParseCron, err)
+	}
+	if spec.hours, err = parseCronField(fields[2], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("%w: hour: %v", ErrParseCron, err)
+	}
+	if spec.days, err = parseCronField(fields[3], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("%w: day of month: %v", ErrParseCron, err)
+	}
+	if spec.months, err = parseCronField(fields[4], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("%w: month: %v", ErrParseCron, err)
+	}
+	if spec.weekdays, err = parseCronField(fields[5], 0, 7, cronWeekdayNames); err != nil {
+		return nil, fmt.Errorf("%w: day of week: %v", ErrParseCron, err)
+	}
+
+	// 7 is an alias for Sunday
+	if spec.weekdays&(1<<7) != 0 {
+		spec.weekdays = spec.weekdays&^(1<<7) | 1
+	}
+
+	// like Vixie cron, a field starting with "*" (e.g. "*/2") counts as unrestricted
+	daysStar := strings.HasPrefix(fields[3], "*") || fields[3] == "?"
+	weekdaysStar := strings.HasPrefix(fields[5], "*") || fields[5] == "?"
+	spec.dayOr = !daysStar && !weekdaysStar
+
+	return spec, nil
+}
+
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var mask uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step '%v'", stepPart)
+			}
+		}
+
+		var low, high int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			low, high = min, max
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseCronValue(lowPart, min, max, names); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(highPart, min, max, names); err != nil {
+				return 0, err
+			}
+			// like the numeric "1-7", SUN is 7 at the end of a range such as "MON-SUN"
+			if max == 7 && low > 0 && strings.EqualFold(highPart, "sun") {
+				high = 7
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range '%v'", rangePart)
+			}
+		default:
+			var err error
+			if low, err = parseCronValue(rangePart, min, max, names); err != nil {
+				return 0, err
+			}
+			high = low
+			// "5/15" starts at 5 and continues until the maximum
+			if hasStep {
+				high = max
+			}
+		}
+
+		for i := low; i <= high; i += step {
+			mask |= 1 << uint(i)
+		}
+	}
+
+	return mask, nil
+}
+
+func parseCronValue(s string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%v'", s)
+	}
+	if value < min || value > max {
+		return 0, fmt.Errorf("value %v out of range (%v-%v)", value, min, max)
+	}
+	return value, nil
+}
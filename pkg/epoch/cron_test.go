@@ -0,0 +1,173 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Friday
+	given := time.Date(2024, 3, 29, 10, 0, 0, 0, berlin)
+
+	testCases := []struct {
+		description string
+		expr        string
+		count       int
+		expected    []time.Time
+		expectedErr error
+	}{
+		{
+			description: "every 15 minutes",
+			expr:        "*/15 * * * *",
+			count:       3,
+			expected: []time.Time{
+				time.Date(2024, 3, 29, 10, 15, 0, 0, berlin),
+				time.Date(2024, 3, 29, 10, 30, 0, 0, berlin),
+				time.Date(2024, 3, 29, 10, 45, 0, 0, berlin),
+			},
+		},
+		{
+			description: "weekdays by name",
+			expr:        "30 9 * * Mon-Fri",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 4, 1, 9, 30, 0, 0, berlin),
+				time.Date(2024, 4, 2, 9, 30, 0, 0, berlin),
+			},
+		},
+		{
+			description: "range to sunday by name",
+			expr:        "0 9 * * SAT-SUN",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 3, 30, 9, 0, 0, 0, berlin),
+				time.Date(2024, 3, 31, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "previous",
+			expr:        "0 12 1 * *",
+			count:       -2,
+			expected: []time.Time{
+				time.Date(2024, 3, 1, 12, 0, 0, 0, berlin),
+				time.Date(2024, 2, 1, 12, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "seconds",
+			expr:        "*/20 0 10 * * *",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 3, 29, 10, 0, 20, 0, berlin),
+				time.Date(2024, 3, 29, 10, 0, 40, 0, berlin),
+			},
+		},
+		{
+			description: "day of month or day of week",
+			expr:        "0 0 13 * 5",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 4, 5, 0, 0, 0, 0, berlin),
+				time.Date(2024, 4, 12, 0, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "sunday as 7",
+			expr:        "0 0 * * 7",
+			count:       1,
+			expected:    []time.Time{time.Date(2024, 3, 31, 0, 0, 0, 0, berlin)},
+		},
+		{
+			description: "macro",
+			expr:        "@monthly",
+			count:       1,
+			expected:    []time.Time{time.Date(2024, 4, 1, 0, 0, 0, 0, berlin)},
+		},
+		{
+			description: "timezone prefix",
+			expr:        "CRON_TZ=UTC 0 12 * * *",
+			count:       1,
+			expected:    []time.Time{time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "skipped by dst",
+			expr:        "30 2 * * *",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 3, 30, 2, 30, 0, 0, berlin),
+				// 2024-03-31 02:30 doesn't exist in Berlin
+				time.Date(2024, 4, 1, 2, 30, 0, 0, berlin),
+			},
+		},
+		{
+			description: "never",
+			expr:        "0 0 30 2 *",
+			count:       1,
+			expectedErr: errors.New("no occurrences"),
+		},
+		{
+			description: "wrong number of fields",
+			expr:        "* * *",
+			expectedErr: errors.New("failed to parse cron expression: expected 5 or 6 fields, got 3"),
+		},
+		{
+			description: "out of range",
+			expr:        "0 24 * * *",
+			expectedErr: errors.New("failed to parse cron expression: hour: value 24 out of range (0-23)"),
+		},
+		{
+			description: "unknown macro",
+			expr:        "@often",
+			expectedErr: errors.New("failed to parse cron expression: unknown macro '@often'"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr, berlin)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+
+			got, err := Occurrences(schedule, given, tt.count)
+			if err != nil || tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				equal(t, errors.Is(err, ErrNoOccurrences), true)
+				return
+			}
+			equal(t, len(got), len(tt.expected))
+			for i := range got {
+				if !got[i].Equal(tt.expected[i]) {
+					t.Fatalf("occurrence %v: got %v, want %v", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseCronRepeatedByDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedule, err := ParseCron("30 2 * * *", berlin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2024-10-27 02:30 exists twice in Berlin, only the first one matches
+	got, err := Occurrences(schedule, time.Date(2024, 10, 26, 12, 0, 0, 0, berlin), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, got[0].Format(TimeFormatGo), "2024-10-27 02:30:00 +0200 CEST")
+	equal(t, got[1].Format(TimeFormatGo), "2024-10-28 02:30:00 +0100 CET")
+}
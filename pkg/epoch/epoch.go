@@ -3,6 +3,9 @@ package epoch
 import (
 	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -21,6 +24,20 @@ const (
 	UnitNanoseconds
 )
 
+// unitNames are the names of the units in notes and errors.
+var unitNames = map[TimeUnit]string{
+	UnitSeconds:      "seconds",
+	UnitMilliseconds: "milliseconds",
+	UnitMicroseconds: "microseconds",
+	UnitNanoseconds:  "nanoseconds",
+}
+
+// ErrUnknownUnit is returned for timestamp units other than seconds, milliseconds, microseconds and nanoseconds.
+var ErrUnknownUnit = errors.New("unknown unit")
+
+// ErrOutOfRange is returned when a value, such as a timestamp, can't be represented.
+var ErrOutOfRange = errors.New("out of range")
+
 // ParseUnit takes a string and returns the corresponding unit.
 func ParseUnit(input string) (TimeUnit, error) {
 	switch input {
@@ -33,11 +50,39 @@ func ParseUnit(input string) (TimeUnit, error) {
 	case "ns", "nano":
 		return UnitNanoseconds, nil
 	}
-	return UnitSeconds, fmt.Errorf("failed to parse input '%v' to unit", input)
+	return UnitSeconds, fmt.Errorf("%w '%v'", ErrUnknownUnit, input)
+}
+
+// timestampRanges are the earliest and latest times of timestamps in the unit, seconds cover all times.
+var timestampRanges = map[TimeUnit][2]time.Time{
+	UnitMilliseconds: {time.UnixMilli(math.MinInt64), time.UnixMilli(math.MaxInt64)},
+	UnitMicroseconds: {time.UnixMicro(math.MinInt64), time.UnixMicro(math.MaxInt64)},
+	UnitNanoseconds:  {time.Unix(0, math.MinInt64), time.Unix(0, math.MaxInt64)},
+}
+
+// ErrUnknownTimezone is returned for timezones missing in the IANA Time Zone database.
+var ErrUnknownTimezone = errors.New("unknown timezone")
+
+// LoadLocation returns the timezone with the name, e.g. "UTC", "America/New_York" or "Local" (case-insensitive).
+func LoadLocation(name string) (*time.Location, error) {
+	if strings.ToLower(name) == "local" {
+		name = "Local" // capital is important
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w '%v': %v", ErrUnknownTimezone, name, err)
+	}
+	return loc, nil
 }
 
 // ToTimestamp takes Go's default time type returns a timestamp of the given unit.
+// Times which don't fit into the unit return ErrOutOfRange, e.g. nanoseconds after the year 2262.
 func ToTimestamp(t time.Time, unit TimeUnit) (int64, error) {
+	if r, ok := timestampRanges[unit]; ok && (t.Before(r[0]) || t.After(r[1])) {
+		return 0, fmt.Errorf("time %v %w for %v (%v to %v)", t.Format(time.RFC3339), ErrOutOfRange, unitNames[unit], r[0].UTC().Format(time.RFC3339), r[1].UTC().Format(time.RFC3339))
+	}
+
 	switch unit {
 	case UnitSeconds:
 		return t.Unix(), nil
@@ -48,7 +93,7 @@ func ToTimestamp(t time.Time, unit TimeUnit) (int64, error) {
 	case UnitNanoseconds:
 		return t.UnixNano(), nil
 	default:
-		return 0, fmt.Errorf("unknown unit '%v'", unit)
+		return 0, fmt.Errorf("%w '%v'", ErrUnknownUnit, unit)
 	}
 }
 
@@ -65,17 +110,13 @@ func ParseTimestamp(timestamp int64, unit TimeUnit) (time.Time, error) {
 	case UnitSeconds:
 		return time.Unix(timestamp, 0), nil
 	case UnitMilliseconds:
-		// add digits to match nanosecond accuracy
-		timestamp *= 1000 * 1000
-		return time.Unix(0, timestamp), nil
+		return time.UnixMilli(timestamp), nil
 	case UnitMicroseconds:
-		// add digits to match nanosecond accuracy
-		timestamp *= 1000
-		return time.Unix(0, timestamp), nil
+		return time.UnixMicro(timestamp), nil
 	case UnitNanoseconds:
 		return time.Unix(0, timestamp), nil
 	default:
-		return time.Time{}, fmt.Errorf("unknown unit '%v'", unit)
+		return time.Time{}, fmt.Errorf("%w '%v'", ErrUnknownUnit, unit)
 	}
 }
 
@@ -122,97 +163,110 @@ const (
 // ParseFormatted takes a human readable time string and returns Go's default time type and the layout it recognized.
 // Example input: "Mon, 02 Jan 2006 15:04:05 MST".
 // TZ is only used for inputs without a specific timezone, such as "2019-01-25 21:51:38".
+// Numeric dates such as "25.01.2019" are parsed with ParseNumericDate when their order is unambiguous,
+// otherwise an *AmbiguousDateError is returned (use a Parser with a DateOrder).
+// Inputs which don't match any layout return a *ParseError.
 func ParseFormatted(input string, tz *time.Location) (time.Time, string, error) {
-	// "Mon, 02 Jan 2006 15:04:05 MST"
-	if t, err := time.ParseInLocation(time.RFC1123, input, tz); err == nil {
-		return t, time.RFC1123, nil
-	}
-
-	// "Mon, 02 Jan 2006 15:04:05 -0700"
-	if t, err := time.ParseInLocation(time.RFC1123Z, input, tz); err == nil {
-		return t, time.RFC1123Z, nil
-	}
-
-	// "2006-01-02T15:04:05Z07:00"
-	if t, err := time.ParseInLocation(time.RFC3339, input, tz); err == nil {
-		return t, time.RFC3339, nil
-	}
-
-	// "2006-01-02T15:04:05.999999999Z07:00"
-	if t, err := time.ParseInLocation(time.RFC3339Nano, input, tz); err == nil {
-		return t, time.RFC3339Nano, nil
-	}
-
-	// "02 Jan 06 15:04 MST"
-	if t, err := time.ParseInLocation(time.RFC822, input, tz); err == nil {
-		return t, time.RFC822, nil
-	}
-
-	// "02 Jan 06 15:04 -0700"
-	if t, err := time.ParseInLocation(time.RFC822Z, input, tz); err == nil {
-		return t, time.RFC822Z, nil
-	}
-
-	// "Monday, 02-Jan-06 15:04:05 MST"
-	if t, err := time.ParseInLocation(time.RFC850, input, tz); err == nil {
-		return t, time.RFC850, nil
-	}
-
-	// "Mon Jan _2 15:04:05 2006"
-	if t, err := time.ParseInLocation(time.ANSIC, input, tz); err == nil {
-		return t, time.ANSIC, nil
-	}
-
-	// "Mon Jan _2 15:04:05 MST 2006"
-	if t, err := time.ParseInLocation(time.UnixDate, input, tz); err == nil {
-		return t, time.UnixDate, nil
+	t, layout, err := parseFormatted(input, tz, DateOrderAuto)
+	if err != nil {
+		return time.Time{}, "", Parser{}.parseError(input, tz, err)
 	}
+	return t, layout, nil
+}
 
-	// "Mon Jan 02 15:04:05 -0700 2006"
-	if t, err := time.ParseInLocation(time.RubyDate, input, tz); err == nil {
-		return t, time.RubyDate, nil
+func parseFormatted(input string, tz *time.Location, order DateOrder) (time.Time, string, error) {
+	for _, layout := range candidateLayouts(input) {
+		if t, err := parseBuiltin(layout, input, tz); err == nil {
+			return t, layout, nil
+		}
 	}
 
-	// "3:04PM"
-	if t, err := time.ParseInLocation(time.Kitchen, input, tz); err == nil {
-		return t, time.Kitchen, nil
+	// "03/04/2024", "25.01.2019 21:51" or "2019-01-25"
+	t, layout, err := ParseNumericDate(input, order, tz)
+	var ambiguous *AmbiguousDateError
+	if err == nil || errors.As(err, &ambiguous) {
+		return t, layout, err
 	}
 
-	// "Jan _2 15:04:05"
-	if t, err := time.ParseInLocation(time.Stamp, input, tz); err == nil {
-		return t, time.Stamp, nil
-	}
+	return time.Time{}, "", ErrParseFormatted
+}
 
-	// "Jan _2 15:04:05.000"
-	if t, err := time.ParseInLocation(time.StampMilli, input, tz); err == nil {
-		return t, time.StampMilli, nil
-	}
+// builtinLayouts are the layouts of ParseFormatted in the order they are tried.
+var builtinLayouts = []string{
+	time.RFC1123,     // "Mon, 02 Jan 2006 15:04:05 MST"
+	time.RFC1123Z,    // "Mon, 02 Jan 2006 15:04:05 -0700"
+	time.RFC3339,     // "2006-01-02T15:04:05Z07:00"
+	time.RFC3339Nano, // "2006-01-02T15:04:05.999999999Z07:00"
+	time.RFC822,      // "02 Jan 06 15:04 MST"
+	time.RFC822Z,     // "02 Jan 06 15:04 -0700"
+	time.RFC850,      // "Monday, 02-Jan-06 15:04:05 MST"
+	time.ANSIC,       // "Mon Jan _2 15:04:05 2006"
+	time.UnixDate,    // "Mon Jan _2 15:04:05 MST 2006"
+	time.RubyDate,    // "Mon Jan 02 15:04:05 -0700 2006"
+	time.Kitchen,     // "3:04PM"
+	time.Stamp,       // "Jan _2 15:04:05"
+	time.StampMilli,  // "Jan _2 15:04:05.000"
+	time.StampMicro,  // "Jan _2 15:04:05.000000"
+	time.StampNano,   // "Jan _2 15:04:05.000000000"
+	TimeFormatHTTP,   // "Mon, 02 Jan 2006 15:04:05 GMT"
+	TimeFormatGo,     // "2006-01-02 15:04:05.999999999 -0700 MST"
+	TimeFormatSimple, // "2006-01-02 15:04:05.999999999"
+}
 
-	// "Jan _2 15:04:05.000000"
-	if t, err := time.ParseInLocation(time.StampMicro, input, tz); err == nil {
-		return t, time.StampMicro, nil
-	}
+// Candidates of the built-in layouts by the shape of the input, each in the order of builtinLayouts.
+var (
+	layoutsWeekdayComma = []string{time.RFC1123, time.RFC1123Z, TimeFormatHTTP}
+	layoutsLongWeekday  = []string{time.RFC850}
+	layoutsNameSpace    = []string{time.ANSIC, time.UnixDate, time.RubyDate, time.Stamp, time.StampMilli, time.StampMicro, time.StampNano}
+	layoutsRFC3339      = []string{time.RFC3339, time.RFC3339Nano}
+	layoutsDateSpace    = []string{TimeFormatGo, TimeFormatSimple}
+	layoutsRFC822       = []string{time.RFC822, time.RFC822Z}
+	layoutsKitchen      = []string{time.Kitchen}
+)
 
-	// "Jan _2 15:04:05.000000000"
-	if t, err := time.ParseInLocation(time.StampNano, input, tz); err == nil {
-		return t, time.StampNano, nil
+// candidateLayouts returns the built-in layouts which might parse the input, based on its
+// leading character and the positions of separators. It never excludes a matching layout.
+func candidateLayouts(input string) []string {
+	if len(input) < 6 {
+		return nil
 	}
 
-	// "Mon, 02 Jan 2006 15:04:05 GMT"
-	if t, err := time.ParseInLocation(TimeFormatHTTP, input, tz); err == nil {
-		return t, TimeFormatHTTP, nil
+	switch c := input[0]; {
+	case isASCIILetter(c):
+		switch {
+		case input[3] == ',':
+			return layoutsWeekdayComma
+		case input[3] == ' ':
+			return layoutsNameSpace
+		case strings.IndexByte(input, ',') > 3:
+			return layoutsLongWeekday
+		}
+	case c >= '0' && c <= '9':
+		switch {
+		case input[4] == '-' && len(input) > 10 && (input[10] == 'T' || input[10] == 't'):
+			return layoutsRFC3339
+		case input[4] == '-':
+			return layoutsDateSpace
+		case input[2] == ' ':
+			return layoutsRFC822
+		case input[1] == ':' || input[2] == ':':
+			return layoutsKitchen
+		}
 	}
+	return nil
+}
 
-	if t, err := time.ParseInLocation(TimeFormatGo, strings.Split(input, " m=")[0], tz); err == nil {
-		return t, TimeFormatGo, nil
-	}
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
 
-	// "2019-01-25 21:51:38"
-	if t, err := time.ParseInLocation(TimeFormatSimple, input, tz); err == nil {
-		return t, TimeFormatSimple, nil
+// parseBuiltin parses the input with a built-in layout.
+func parseBuiltin(layout, input string, tz *time.Location) (time.Time, error) {
+	if layout == TimeFormatGo {
+		// e.g. "2019-01-26 09:43:57.377055 +0100 CET m=+0.644739467" from time.Now().String()
+		input, _, _ = strings.Cut(input, " m=")
 	}
-
-	return time.Time{}, "", ErrParseFormatted
+	return time.ParseInLocation(layout, input, tz)
 }
 
 // Operator for arithemtic operation.
@@ -225,6 +279,14 @@ const (
 	Add
 	// Sub operation.
 	Sub
+	// Truncate operation, moves to the start of the unit (e.g. start of the day).
+	Truncate
+	// Round operation, moves to the nearest start of the unit.
+	Round
+	// End operation, moves to the last nanosecond of the unit (e.g. end of the day).
+	End
+	// Set operation, replaces the component of the unit (e.g. the hour).
+	Set
 )
 
 // ErrUnkownOperator is returned when no matching operator was found.
@@ -237,15 +299,34 @@ func ToOperator(s string) (Operator, error) {
 		return Add, nil
 	case "-": //, "sub", "minus":
 		return Sub, nil
+	case "/", "startof":
+		return Truncate, nil
+	case "round":
+		return Round, nil
+	case "endof":
+		return End, nil
+	case "=":
+		return Set, nil
 	}
 	return Undefined, fmt.Errorf("%w: '%v'", ErrUnkownOperator, s)
 }
 
 // Calculate does basic add/sub calculations on the given input.
+// Truncate, Round and End ignore the amount and align the input to the boundaries of the unit.
+// Set replaces the component of the unit with the amount, see SetComponent.
+// Business days ("B") use the default Calendar, use Calculator for other calendars and to report errors.
 func Calculate(input time.Time, op Operator, amount int, unit string) time.Time {
 	switch op {
 	case Sub:
 		amount = -amount
+	case Truncate:
+		return StartOf(input, unit)
+	case Round:
+		return RoundTo(input, unit)
+	case End:
+		return EndOf(input, unit)
+	case Set:
+		return SetComponent(input, amount, unit)
 	}
 
 	var duration time.Duration
@@ -275,15 +356,370 @@ func Calculate(input time.Time, op Operator, amount int, unit string) time.Time
 		return input.AddDate(0, 0, amount*7)
 	case "M":
 		return input.AddDate(0, amount, 0)
+	case "Q":
+		return input.AddDate(0, amount*3, 0)
 	case "Y":
 		return input.AddDate(amount, 0, 0)
+	case "B":
+		// the default calendar always has business days
+		t, _ := Calendar{}.AddBusinessDays(input, amount)
+		return t
 	}
 
 	return time.Time{}
 }
 
+// Calculation is a single step of a calculation expression, e.g. "+30m" or "/D".
+type Calculation struct {
+	Operator Operator
+	Amount   int
+	Unit     string
+}
+
+// ErrUnknownCalcUnit is returned when a calculation uses an unsupported unit.
+var ErrUnknownCalcUnit = errors.New("unknown calculation unit")
+
+// calcUnits are all units supported by Calculate.
+var calcUnits = []string{"ns", "us", "ms", "s", "m", "h", "D", "W", "M", "Q", "Y", "B"}
+
+// boundaryUnits are all units supported by StartOf, EndOf and RoundTo.
+var boundaryUnits = []string{"ns", "us", "ms", "s", "m", "h", "D", "W", "M", "Q", "Y"}
+
+// ParseCalculations parses a whitespace separated calculation expression.
+// Supported steps are "+<amount><unit>" and "-<amount><unit>" for arithmetics,
+// "=<value><unit>" to set a component, "/<unit>" or "startof:<unit>" to truncate,
+// "round:<unit>" to round and "endof:<unit>" to move to the end of the unit,
+// e.g. "-1D /D" for the start of yesterday or "=9h =0m =0s" for today at 09:00.
+func ParseCalculations(input string) ([]Calculation, error) {
+	var calculations []Calculation
+
+	for _, step := range strings.Fields(input) {
+		calc, err := parseCalculation(step)
+		if err != nil {
+			return nil, err
+		}
+		calculations = append(calculations, calc)
+	}
+
+	return calculations, nil
+}
+
+func parseCalculation(step string) (Calculation, error) {
+	// named operators, e.g. "startof:W"
+	if name, unit, found := strings.Cut(step, ":"); found {
+		operator, err := ToOperator(name)
+		if err != nil {
+			return Calculation{}, err
+		}
+		if operator == Add || operator == Sub {
+			return Calculation{}, fmt.Errorf("%w: '%v'", ErrUnkownOperator, name)
+		}
+		if !slices.Contains(boundaryUnits, unit) {
+			return Calculation{}, fmt.Errorf("%w: '%v'", ErrUnknownCalcUnit, unit)
+		}
+		return Calculation{Operator: operator, Unit: unit}, nil
+	}
+
+	operator, err := ToOperator(step[:1])
+	if err != nil {
+		return Calculation{}, err
+	}
+
+	rest := step[1:]
+	digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+
+	unit := rest[digits:]
+	if !slices.Contains(calcUnits, unit) {
+		return Calculation{}, fmt.Errorf("%w: '%v'", ErrUnknownCalcUnit, unit)
+	}
+
+	if operator == Truncate {
+		if digits != 0 {
+			return Calculation{}, fmt.Errorf("truncation doesn't take an amount: '%v'", step)
+		}
+		if !slices.Contains(boundaryUnits, unit) {
+			return Calculation{}, fmt.Errorf("%w: '%v'", ErrUnknownCalcUnit, unit)
+		}
+		return Calculation{Operator: operator, Unit: unit}, nil
+	}
+
+	amount, err := strconv.Atoi(rest[:digits])
+	if err != nil {
+		return Calculation{}, fmt.Errorf("failed to parse amount of '%v': %w", step, err)
+	}
+
+	if operator == Set {
+		if err := validateSet(amount, unit); err != nil {
+			return Calculation{}, err
+		}
+	}
+
+	return Calculation{Operator: operator, Amount: amount, Unit: unit}, nil
+}
+
+// Overflow defines how month, quarter and year arithmetics handle days which don't exist in the resulting month.
+type Overflow uint
+
+const (
+	// OverflowNormalize normalizes the date like time.AddDate, e.g. 2024-01-31 +1M results in 2024-03-02.
+	OverflowNormalize Overflow = iota
+	// OverflowClamp clamps the day to the end of the month, e.g. 2024-01-31 +1M results in 2024-02-29.
+	OverflowClamp
+	// OverflowError returns ErrDateOverflow.
+	OverflowError
+)
+
+// ErrDateOverflow is returned by OverflowError when the resulting day doesn't exist.
+var ErrDateOverflow = errors.New("day doesn't exist in the resulting month")
+
+// ParseOverflow returns the overflow policy of the given name ("normalize", "clamp" or "error").
+func ParseOverflow(s string) (Overflow, error) {
+	switch strings.ToLower(s) {
+	case "", "normalize":
+		return OverflowNormalize, nil
+	case "clamp":
+		return OverflowClamp, nil
+	case "error":
+		return OverflowError, nil
+	}
+	return OverflowNormalize, fmt.Errorf("failed to parse overflow policy '%v'", s)
+}
+
+// AddMonths adds the amount of months to t and handles non-existent days according to the overflow policy.
+func AddMonths(t time.Time, months int, overflow Overflow) (time.Time, error) {
+	if overflow == OverflowNormalize {
+		return t.AddDate(0, months, 0), nil
+	}
+
+	year, month, day := t.Date()
+	// normalize the month without the day, e.g. month 13 is January of the next year
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		if overflow == OverflowError {
+			return time.Time{}, fmt.Errorf("%w: %v-%02d-%02d", ErrDateOverflow, first.Year(), int(first.Month()), day)
+		}
+		day = last
+	}
+
+	hour, min, sec := t.Clock()
+	return time.Date(first.Year(), first.Month(), day, hour, min, sec, t.Nanosecond(), t.Location()), nil
+}
+
+// Mode defines how additions and subtractions behave across DST transitions.
+type Mode uint
+
+const (
+	// ModeDefault uses ModeElapsed for time units ("ns" to "h") and ModeWallClock for days ("D") and weeks ("W").
+	ModeDefault Mode = iota
+	// ModeElapsed adds the physically elapsed time, e.g. +1D always adds 24 hours,
+	// even when the day only has 23 hours because of a DST transition.
+	ModeElapsed
+	// ModeWallClock changes the wall clock time in the location, e.g. +24h keeps the time of day,
+	// even when 23 or 25 hours elapsed because of a DST transition.
+	ModeWallClock
+)
+
+// ParseMode returns the mode of the given name ("elapsed" or "wall").
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(s) {
+	case "", "default":
+		return ModeDefault, nil
+	case "elapsed":
+		return ModeElapsed, nil
+	case "wall", "wallclock":
+		return ModeWallClock, nil
+	}
+	return ModeDefault, fmt.Errorf("failed to parse mode '%v'", s)
+}
+
+// String returns the calculation in the syntax of ParseCalculations.
+func (c Calculation) String() string {
+	switch c.Operator {
+	case Add:
+		return fmt.Sprintf("+%v%v", c.Amount, c.Unit)
+	case Sub:
+		return fmt.Sprintf("-%v%v", c.Amount, c.Unit)
+	case Set:
+		return fmt.Sprintf("=%v%v", c.Amount, c.Unit)
+	case Truncate:
+		return "/" + c.Unit
+	case Round:
+		return "round:" + c.Unit
+	case End:
+		return "endof:" + c.Unit
+	}
+	return fmt.Sprintf("%v%v", c.Amount, c.Unit)
+}
+
+// unitDurations are the units with a fixed duration.
+var unitDurations = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"D":  24 * time.Hour,
+	"W":  7 * 24 * time.Hour,
+}
+
+// multiplyDuration returns amount times the duration of the unit.
+// It returns ErrOutOfRange when the result doesn't fit into a time.Duration.
+func multiplyDuration(amount int, unit string) (time.Duration, error) {
+	duration, ok := scaleDuration(amount, unitDurations[unit])
+	if !ok {
+		return 0, fmt.Errorf("%v%v %w", amount, unit, ErrOutOfRange)
+	}
+	return duration, nil
+}
+
+// scaleDuration returns amount times d, false when it doesn't fit into a time.Duration.
+func scaleDuration(amount int, d time.Duration) (time.Duration, bool) {
+	max := math.MaxInt64 / int64(d)
+	if int64(amount) > max || int64(amount) < -max {
+		return 0, false
+	}
+	return time.Duration(amount) * d, true
+}
+
+// addWallClock adds the duration to the wall clock time of t in its location.
+func addWallClock(t time.Time, d time.Duration) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+
+	wall := time.Date(year, month, day, hour, min, sec, t.Nanosecond(), time.UTC).Add(d)
+
+	year, month, day = wall.Date()
+	hour, min, sec = wall.Clock()
+	return time.Date(year, month, day, hour, min, sec, wall.Nanosecond(), t.Location())
+}
+
+// Calculator applies calculations with configurable behaviour.
+// The zero value behaves like Calculate.
+type Calculator struct {
+	// Calendar is used for business days ("B"). Saturday and Sunday without any holidays are used when nil.
+	Calendar *Calendar
+	// Overflow is used for month ("M"), quarter ("Q") and year ("Y") arithmetics.
+	Overflow Overflow
+	// TimeMode is used for additions and subtractions of "ns", "us", "ms", "s", "m" and "h".
+	// ModeDefault uses ModeElapsed.
+	TimeMode Mode
+	// DateMode is used for additions and subtractions of "D" and "W".
+	// ModeDefault uses ModeWallClock.
+	DateMode Mode
+	// Warn is called (when not nil) with a description when an addition or subtraction crosses a DST transition.
+	Warn func(msg string)
+}
+
+// Calculate applies a single calculation to the input.
+func (c Calculator) Calculate(input time.Time, calc Calculation) (time.Time, error) {
+	result, err := c.calculate(input, calc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if c.Warn != nil && (calc.Operator == Add || calc.Operator == Sub) {
+		warnDST(c.Warn, calc, input, result)
+	}
+
+	return result, nil
+}
+
+// warnDST calls warn when the offsets of input and result differ.
+func warnDST(warn func(msg string), calc Calculation, input, result time.Time) {
+	_, inputOffset := input.Zone()
+	_, resultOffset := result.Zone()
+
+	if inputOffset != resultOffset {
+		elapsed := result.Sub(input)
+		wall := elapsed + time.Duration(resultOffset-inputOffset)*time.Second
+
+		warn(fmt.Sprintf("%v crosses a DST transition (%v to %v): %v elapsed, wall clock changed by %v",
+			calc, input.Format("MST -0700"), result.Format("MST -0700"), elapsed, wall,
+		))
+	}
+}
+
+func (c Calculator) calculate(input time.Time, calc Calculation) (time.Time, error) {
+	if !slices.Contains(calcUnits, calc.Unit) {
+		return time.Time{}, fmt.Errorf("%w: '%v'", ErrUnknownCalcUnit, calc.Unit)
+	}
+
+	// calculations which weren't parsed might set components which don't exist, e.g. weeks
+	if calc.Operator == Set {
+		if err := validateSet(calc.Amount, calc.Unit); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if calc.Unit == "B" {
+		calendar := Calendar{}
+		if c.Calendar != nil {
+			calendar = *c.Calendar
+		}
+
+		switch calc.Operator {
+		case Add:
+			return calendar.AddBusinessDays(input, calc.Amount)
+		case Sub:
+			return calendar.AddBusinessDays(input, -calc.Amount)
+		default:
+			return time.Time{}, fmt.Errorf("%w: business days only support '+' and '-'", ErrUnkownOperator)
+		}
+	}
+
+	if calc.Operator == Add || calc.Operator == Sub {
+		amount := calc.Amount
+		if calc.Operator == Sub {
+			amount = -amount
+		}
+
+		switch calc.Unit {
+		case "ns", "us", "ms", "s", "m", "h":
+			duration, err := multiplyDuration(amount, calc.Unit)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if c.TimeMode == ModeWallClock {
+				return addWallClock(input, duration), nil
+			}
+			return input.Add(duration), nil
+		case "D", "W":
+			if c.DateMode == ModeElapsed {
+				duration, err := multiplyDuration(amount, calc.Unit)
+				if err != nil {
+					return time.Time{}, err
+				}
+				return input.Add(duration), nil
+			}
+		case "M":
+			return AddMonths(input, amount, c.Overflow)
+		case "Q":
+			return AddMonths(input, amount*3, c.Overflow)
+		case "Y":
+			return AddMonths(input, amount*12, c.Overflow)
+		}
+	}
+
+	return Calculate(input, calc.Operator, calc.Amount, calc.Unit), nil
+}
+
+// Apply applies all calculations in the given order to the input.
+func (c Calculator) Apply(input time.Time, calculations []Calculation) (time.Time, error) {
+	var err error
+	for _, calc := range calculations {
+		input, err = c.Calculate(input, calc)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return input, nil
+}
+
 // FormatName returns the formatting to the given name (e.g. 'unix' or 'rfc3339').
 // When 'format' is not recognized, it will return Go's default format and an error.
+// 'relative' returns FormatRelative, which is no layout but formatted with Relative.
 func FormatName(format string) (string, error) {
 	format = strings.ToLower(format)
 
@@ -322,70 +758,40 @@ func FormatName(format string) (string, error) {
 		return time.StampNano, nil
 	case "http":
 		return TimeFormatHTTP, nil
+	case FormatRelative:
+		return FormatRelative, nil
 	default:
 		return TimeFormatGo, fmt.Errorf("failed to parse format %q", format)
 	}
 }
 
 // FormatSimple converts a simple format to Go's native formatting.
+// Unknown tokens and tokens without a Go equivalent (e.g. "{WW}") are kept as they are.
+//
+// Deprecated: Literal text can't be escaped in Go layouts, use ParseSimpleFormat instead.
 func FormatSimple(format string) string {
-	format = strings.ReplaceAll(format, "{YYYY}", "2006") // Long year
-	format = strings.ReplaceAll(format, "{YY}", "06")     // Short year
-
-	format = strings.ReplaceAll(format, "{MMMM}", "January")
-	format = strings.ReplaceAll(format, "{MMM}", "Jan")
-	format = strings.ReplaceAll(format, "{MM}", "01") // Month (2-digit)
-	format = strings.ReplaceAll(format, "{M}", "1")   // Month (1-digit)
-
-	format = strings.ReplaceAll(format, "{DDDD}", "002") // Day of year
-	format = strings.ReplaceAll(format, "{DDDD}", "__2") // Day of year
-
-	format = strings.ReplaceAll(format, "{DD}", "02") // Day of month (2-digit)
-	format = strings.ReplaceAll(format, "{D}", "2")   // Day of month (1-digit)
-
-	format = strings.ReplaceAll(format, "{dddd}", "Monday") // Day of week
-	format = strings.ReplaceAll(format, "{ddd}", "Mon")     // Day of week
-
-	format = strings.ReplaceAll(format, "{HH}", "15") // Hour 24 (2-digit)
-	format = strings.ReplaceAll(format, "{hh}", "03") // Hour 12 (2-digit)
-	format = strings.ReplaceAll(format, "{h}", "3")   // Hour 12 (1-digit)
-
-	format = strings.ReplaceAll(format, "{A}", "PM")
-	format = strings.ReplaceAll(format, "{a}", "pm")
-
-	format = strings.ReplaceAll(format, "{mm}", "04") // Minute (2-digit)
-	format = strings.ReplaceAll(format, "{m}", "4")   // Minute (1-digit)
-
-	format = strings.ReplaceAll(format, "{ss}", "05") // Second (2-digit)
-	format = strings.ReplaceAll(format, "{s}", "5")   // Second (1-digit)
-
-	// Arbitrary precision of fractional seconds.
-	// The dot won't be removed from the output :-/
-	format = strings.ReplaceAll(format, "{F}", ".0")
-	format = strings.ReplaceAll(format, "{FF}", ".00")
-	format = strings.ReplaceAll(format, "{FFF}", ".000")
-	format = strings.ReplaceAll(format, "{FFFF}", ".0000")
-	format = strings.ReplaceAll(format, "{FFFFF}", ".00000")
-	format = strings.ReplaceAll(format, "{FFFFFF}", ".000000")
-	format = strings.ReplaceAll(format, "{FFFFFFF}", ".0000000")
-	format = strings.ReplaceAll(format, "{FFFFFFFF}", ".00000000")
-	format = strings.ReplaceAll(format, "{FFFFFFFFF}", ".000000000")
-
-	format = strings.ReplaceAll(format, "{f}", ".9")
-	format = strings.ReplaceAll(format, "{ff}", ".99")
-	format = strings.ReplaceAll(format, "{fff}", ".999")
-	format = strings.ReplaceAll(format, "{ffff}", ".9999")
-	format = strings.ReplaceAll(format, "{fffff}", ".99999")
-	format = strings.ReplaceAll(format, "{ffffff}", ".999999")
-	format = strings.ReplaceAll(format, "{fffffff}", ".9999999")
-	format = strings.ReplaceAll(format, "{ffffffff}", ".99999999")
-	format = strings.ReplaceAll(format, "{fffffffff}", ".999999999")
-
-	// Timezone / Offset
-	format = strings.ReplaceAll(format, "{ZZZ}", "-07:00")
-	format = strings.ReplaceAll(format, "{ZZ}", "-0700")
-	format = strings.ReplaceAll(format, "{Z}", "-07")
-	format = strings.ReplaceAll(format, "{z}", "MST")
-
-	return format
+	var sb strings.Builder
+	for len(format) > 0 {
+		start := strings.IndexByte(format, '{')
+		if start < 0 {
+			sb.WriteString(format)
+			break
+		}
+		end := strings.IndexByte(format[start:], '}')
+		if end < 0 {
+			sb.WriteString(format)
+			break
+		}
+		end += start
+		sb.WriteString(format[:start])
+
+		token, ok := simpleTokens[format[start+1:end]]
+		if ok && token.layout != "" {
+			sb.WriteString(token.layout)
+		} else {
+			sb.WriteString(format[start : end+1])
+		}
+		format = format[end+1:]
+	}
+	return sb.String()
 }
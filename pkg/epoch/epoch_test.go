@@ -35,7 +35,7 @@ func TestParseUnit(t *testing.T) {
 		{
 			description: "empty",
 			given:       "",
-			expectedErr: errors.New("failed to parse input '' to unit"),
+			expectedErr: errors.New("unknown unit ''"),
 		},
 		{
 			description: "seconds",
@@ -104,6 +104,11 @@ func TestToTimestamp(t *testing.T) {
 			given:       givenType{time: time.Unix(0, 1549727875568573000), unit: UnitSeconds},
 			expected:    expecedType{timestamp: 1549727875},
 		},
+		{
+			description: "out of range",
+			given:       givenType{time: time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC), unit: UnitNanoseconds},
+			expected:    expecedType{err: errors.New("time 2300-01-01T00:00:00Z out of range for nanoseconds (1677-09-21T00:12:43Z to 2262-04-11T23:47:16Z)")},
+		},
 		{
 			description: "milliseconds",
 			given:       givenType{time: time.Unix(0, 1549727875568573000), unit: UnitMilliseconds},
@@ -137,6 +142,34 @@ func TestToTimestamp(t *testing.T) {
 	}
 }
 
+func TestLoadLocation(t *testing.T) {
+	testCases := []struct {
+		description string
+		given       string
+		expected    string
+		expectedErr error
+	}{
+		{description: "local", given: "local", expected: "Local"},
+		{description: "iana", given: "Europe/Berlin", expected: "Europe/Berlin"},
+		{description: "unknown", given: "Mars/Olympus", expectedErr: errors.New("unknown timezone 'Mars/Olympus': unknown time zone Mars/Olympus")},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			loc, err := LoadLocation(tt.given)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				equal(t, errors.Is(err, ErrUnknownTimezone), true)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, loc.String(), tt.expected)
+		})
+	}
+}
+
 func TestParseTimestamp(t *testing.T) {
 	type givenType struct {
 		timestamp int64
@@ -270,7 +303,7 @@ func TestParseFormatted(t *testing.T) {
 		{
 			description: "empty",
 			given:       givenType{formatted: ""},
-			expected:    expecedType{err: ErrParseFormatted, layout: ""},
+			expected:    expecedType{err: errors.New("failed to convert string to time: '' doesn't match any of 18 layouts"), layout: ""},
 		},
 		{
 			description: "rfc1123",
@@ -324,6 +357,26 @@ func TestFormatSimple(t *testing.T) {
 			format: "{s}-{m}",
 			want:   "5-6",
 		},
+		{
+			name:   "fractions",
+			format: "{ss}{FFF} {ss}{FFFFFF}",
+			want:   "05.000 05.000000",
+		},
+		{
+			name:   "day of year",
+			format: "{DDDD}|{DDD}",
+			want:   "251|251",
+		},
+		{
+			name:   "closing brace before token",
+			format: "a} {YYYY}",
+			want:   "a} 2022",
+		},
+		{
+			name:   "unknown token",
+			format: "{YYYY}-{WW}",
+			want:   "2022-{WW}",
+		},
 	}
 
 	for _, tt := range tests {
@@ -349,3 +402,432 @@ func TestFormatSimple(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCalculations(t *testing.T) {
+	testCases := []struct {
+		description string
+		given       string
+		expected    []Calculation
+		expectedErr error
+	}{
+		{
+			description: "empty",
+			given:       "",
+		},
+		{
+			description: "arithmetics",
+			given:       "+30m -5h +250ms",
+			expected: []Calculation{
+				{Operator: Add, Amount: 30, Unit: "m"},
+				{Operator: Sub, Amount: 5, Unit: "h"},
+				{Operator: Add, Amount: 250, Unit: "ms"},
+			},
+		},
+		{
+			description: "boundaries",
+			given:       "/D startof:W round:h endof:Q",
+			expected: []Calculation{
+				{Operator: Truncate, Unit: "D"},
+				{Operator: Truncate, Unit: "W"},
+				{Operator: Round, Unit: "h"},
+				{Operator: End, Unit: "Q"},
+			},
+		},
+		{
+			description: "unknown operator",
+			given:       "*5h",
+			expectedErr: errors.New("unkown operator: '*'"),
+		},
+		{
+			description: "unknown named operator",
+			given:       "+:h",
+			expectedErr: errors.New("unkown operator: '+'"),
+		},
+		{
+			description: "unknown unit",
+			given:       "+5x",
+			expectedErr: errors.New("unknown calculation unit: 'x'"),
+		},
+		{
+			description: "missing amount",
+			given:       "+h",
+			expectedErr: errors.New(`failed to parse amount of '+h': strconv.Atoi: parsing "": invalid syntax`),
+		},
+		{
+			description: "truncation with amount",
+			given:       "/5D",
+			expectedErr: errors.New("truncation doesn't take an amount: '/5D'"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			calculations, err := ParseCalculations(tt.given)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, calculations, tt.expected)
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	type givenType struct {
+		time     time.Time
+		months   int
+		overflow Overflow
+	}
+
+	type expecedType struct {
+		time time.Time
+		err  error
+	}
+
+	testCases := []struct {
+		description string
+		given       givenType
+		expected    expecedType
+	}{
+		{
+			description: "normalize/month end",
+			given:       givenType{time: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), months: 1},
+			expected:    expecedType{time: time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "clamp/leap year",
+			given:       givenType{time: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), months: 1, overflow: OverflowClamp},
+			expected:    expecedType{time: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "clamp/non-leap year",
+			given:       givenType{time: time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC), months: 1, overflow: OverflowClamp},
+			expected:    expecedType{time: time.Date(2023, 2, 28, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "clamp/leap day plus one year",
+			given:       givenType{time: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), months: 12, overflow: OverflowClamp},
+			expected:    expecedType{time: time.Date(2025, 2, 28, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "clamp/leap day plus four years",
+			given:       givenType{time: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), months: 48, overflow: OverflowClamp},
+			expected:    expecedType{time: time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "clamp/backwards over year",
+			given:       givenType{time: time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC), months: -13, overflow: OverflowClamp},
+			expected:    expecedType{time: time.Date(2023, 2, 28, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "clamp/existing day",
+			given:       givenType{time: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), months: 1, overflow: OverflowClamp},
+			expected:    expecedType{time: time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "error",
+			given:       givenType{time: time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC), months: 1, overflow: OverflowError},
+			expected:    expecedType{err: errors.New("day doesn't exist in the resulting month: 2024-06-31")},
+		},
+		{
+			description: "error/existing day",
+			given:       givenType{time: time.Date(2024, 5, 30, 12, 0, 0, 0, time.UTC), months: 1, overflow: OverflowError},
+			expected:    expecedType{time: time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := AddMonths(tt.given.time, tt.given.months, tt.given.overflow)
+			if err != nil {
+				equalError(t, err, tt.expected.err)
+				return
+			} else if tt.expected.err != nil {
+				equalError(t, err, tt.expected.err)
+				return
+			}
+			equal(t, got, tt.expected.time)
+		})
+	}
+}
+
+func TestCalculatorOverflow(t *testing.T) {
+	calculations, err := ParseCalculations("+1Y")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Calculator{Overflow: OverflowClamp}.Apply(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), calculations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, got, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC))
+
+	_, err = Calculator{Overflow: OverflowError}.Apply(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), calculations)
+	equal(t, errors.Is(err, ErrDateOverflow), true)
+}
+
+func TestCalculatorMode(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the day before the DST transition on 2020-10-25 (25 hours)
+	given := time.Date(2020, 10, 24, 12, 0, 0, 0, berlin)
+
+	testCases := []struct {
+		description string
+		calculator  Calculator
+		calc        Calculation
+		expected    time.Time
+	}{
+		{
+			description: "hours/default",
+			calc:        Calculation{Operator: Add, Amount: 24, Unit: "h"},
+			expected:    time.Date(2020, 10, 25, 11, 0, 0, 0, berlin),
+		},
+		{
+			description: "hours/wall clock",
+			calculator:  Calculator{TimeMode: ModeWallClock},
+			calc:        Calculation{Operator: Add, Amount: 24, Unit: "h"},
+			expected:    time.Date(2020, 10, 25, 12, 0, 0, 0, berlin),
+		},
+		{
+			description: "days/default",
+			calc:        Calculation{Operator: Add, Amount: 1, Unit: "D"},
+			expected:    time.Date(2020, 10, 25, 12, 0, 0, 0, berlin),
+		},
+		{
+			description: "days/elapsed",
+			calculator:  Calculator{DateMode: ModeElapsed},
+			calc:        Calculation{Operator: Add, Amount: 1, Unit: "D"},
+			expected:    time.Date(2020, 10, 25, 11, 0, 0, 0, berlin),
+		},
+		{
+			description: "weeks/elapsed/backwards",
+			calculator:  Calculator{DateMode: ModeElapsed},
+			calc:        Calculation{Operator: Sub, Amount: 1, Unit: "W"},
+			expected:    time.Date(2020, 10, 17, 12, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := tt.calculator.Calculate(given, tt.calc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
+
+func TestCalculatorModeOutOfRange(t *testing.T) {
+	given := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description string
+		calculator  Calculator
+		calc        Calculation
+	}{
+		{
+			description: "weeks/elapsed",
+			calculator:  Calculator{DateMode: ModeElapsed},
+			calc:        Calculation{Operator: Add, Amount: 20000, Unit: "W"},
+		},
+		{
+			description: "days/elapsed/backwards",
+			calculator:  Calculator{DateMode: ModeElapsed},
+			calc:        Calculation{Operator: Sub, Amount: 200000, Unit: "D"},
+		},
+		{
+			description: "hours/default",
+			calc:        Calculation{Operator: Add, Amount: 3000000, Unit: "h"},
+		},
+		{
+			description: "hours/wall clock",
+			calculator:  Calculator{TimeMode: ModeWallClock},
+			calc:        Calculation{Operator: Add, Amount: 3000000, Unit: "h"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			_, err := tt.calculator.Calculate(given, tt.calc)
+			equal(t, errors.Is(err, ErrOutOfRange), true)
+		})
+	}
+}
+
+func TestCalculatorWarn(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var warnings []string
+	calculator := Calculator{Warn: func(msg string) { warnings = append(warnings, msg) }}
+
+	_, err = calculator.Calculate(time.Date(2020, 10, 24, 12, 0, 0, 0, berlin), Calculation{Operator: Add, Amount: 1, Unit: "D"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = calculator.Calculate(time.Date(2020, 10, 26, 12, 0, 0, 0, berlin), Calculation{Operator: Add, Amount: 1, Unit: "D"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, warnings, []string{"+1D crosses a DST transition (CEST +0200 to CET +0100): 25h0m0s elapsed, wall clock changed by 24h0m0s"})
+}
+
+// formattedSamples are inputs of every built-in layout and some which don't match any.
+var formattedSamples = []string{
+	"Mon, 02 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05Z",
+	"2006-01-02t15:04:05+01:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"02 Jan 06 15:04 MST",
+	"02 Jan 06 15:04 -0700",
+	"Monday, 02-Jan-06 15:04:05 MST",
+	"Mon Jan  2 15:04:05 2006",
+	"Mon Jan  2 15:04:05 MST 2006",
+	"Mon Jan 02 15:04:05 -0700 2006",
+	"3:04PM",
+	"11:04AM",
+	"Jan  2 15:04:05",
+	"Jan 12 15:04:05.000",
+	"Jan  2 15:04:05.000000",
+	"Jan  2 15:04:05.000000000",
+	"Mon, 02 Jan 2006 15:04:05 GMT",
+	"2019-01-26 09:43:57.377055 +0100 CET m=+0.644739467",
+	"2019-01-26 09:43:57.377055 +0100 CET",
+	"2019-01-25 21:51:38",
+	"2019-01-25 21:51:38.272",
+	"mon, 02 jan 2006 15:04:05 MST",
+	"",
+	"3:04",
+	"Monday",
+	"1548449498",
+	"25.01.2019 21:51",
+	"2019-01-25",
+	"Jan 2006",
+	"foo, bar baz",
+	"0000-00-00T00:00:00Z",
+}
+
+// parseSequential tries all built-in layouts, as ParseFormatted did before detecting candidates.
+func parseSequential(input string, tz *time.Location) (time.Time, string, error) {
+	for _, layout := range builtinLayouts {
+		if t, err := parseBuiltin(layout, input, tz); err == nil {
+			return t, layout, nil
+		}
+	}
+	return time.Time{}, "", ErrParseFormatted
+}
+
+func TestCandidateLayouts(t *testing.T) {
+	for _, input := range formattedSamples {
+		t.Run(input, func(t *testing.T) {
+			want, wantLayout, wantErr := parseSequential(input, time.UTC)
+			if wantErr != nil {
+				for _, layout := range candidateLayouts(input) {
+					_, err := parseBuiltin(layout, input, time.UTC)
+					equal(t, err != nil, true)
+				}
+				return
+			}
+
+			got, layout, err := parseFormatted(input, time.UTC, DateOrderAuto)
+			equal(t, err, nil)
+			equal(t, got, want)
+			equal(t, layout, wantLayout)
+		})
+	}
+}
+
+func TestStickyParser(t *testing.T) {
+	parser := StickyParser{Parser: Parser{DateOrder: DateOrderAuto}}
+
+	testCases := []struct {
+		input        string
+		expected     time.Time
+		expectedName string
+		expectedErr  error
+	}{
+		{input: "13/04/2024", expected: time.Date(2024, 4, 13, 0, 0, 0, 0, time.UTC), expectedName: "02/01/2006"},
+		// ambiguous, but follows the order of the previous date
+		{input: "03/04/2024", expected: time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC), expectedName: "02/01/2006"},
+		{input: "13/4/2024", expected: time.Date(2024, 4, 13, 0, 0, 0, 0, time.UTC), expectedName: "02/1/2006"},
+		{input: "03/4/2024", expected: time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC), expectedName: "02/1/2006"},
+		{input: "2019-01-25T21:51:38Z", expected: time.Date(2019, 1, 25, 21, 51, 38, 0, time.UTC), expectedName: time.RFC3339},
+		{input: "2019-01-26T09:43:57Z", expected: time.Date(2019, 1, 26, 9, 43, 57, 0, time.UTC), expectedName: time.RFC3339},
+		{input: "foo", expectedErr: errors.New("failed to convert string to time: 'foo' doesn't match any of 18 layouts")},
+		{input: "2019-01-26T09:43:58Z", expected: time.Date(2019, 1, 26, 9, 43, 58, 0, time.UTC), expectedName: time.RFC3339},
+	}
+
+	for _, tt := range testCases {
+		got, name, err := parser.Parse(tt.input, time.UTC)
+		if err != nil {
+			equalError(t, err, tt.expectedErr)
+			continue
+		} else if tt.expectedErr != nil {
+			equalError(t, err, tt.expectedErr)
+			continue
+		}
+		equal(t, got, tt.expected)
+		equal(t, name, tt.expectedName)
+	}
+}
+
+func BenchmarkParseFormatted(b *testing.B) {
+	for b.Loop() {
+		for _, input := range formattedSamples {
+			_, _, _ = ParseFormatted(input, time.UTC)
+		}
+	}
+}
+
+// BenchmarkParseSequential is the baseline for BenchmarkParseFormatted.
+func BenchmarkParseSequential(b *testing.B) {
+	for b.Loop() {
+		for _, input := range formattedSamples {
+			_, _, _ = parseSequential(input, time.UTC)
+		}
+	}
+}
+
+// benchmarkStream parses log lines in the same layout.
+func benchmarkStream(b *testing.B, parse func(input string) (time.Time, string, error)) {
+	lines := make([]string, 0, 1000)
+	start := time.Date(2019, 1, 26, 9, 43, 57, 0, time.UTC)
+	for i := range cap(lines) {
+		lines = append(lines, start.Add(time.Duration(i)*time.Second).Format(TimeFormatSimple))
+	}
+
+	for b.Loop() {
+		for _, line := range lines {
+			if _, _, err := parse(line); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkStreamParser(b *testing.B) {
+	parser := Parser{}
+	benchmarkStream(b, func(input string) (time.Time, string, error) { return parser.Parse(input, time.UTC) })
+}
+
+func BenchmarkStreamStickyParser(b *testing.B) {
+	parser := StickyParser{}
+	benchmarkStream(b, func(input string) (time.Time, string, error) { return parser.Parse(input, time.UTC) })
+}
+
+func BenchmarkStreamSequential(b *testing.B) {
+	benchmarkStream(b, func(input string) (time.Time, string, error) { return parseSequential(input, time.UTC) })
+}
@@ -0,0 +1,227 @@
+package epoch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// EXIF tags of times (EXIF 2.31), the offsets and sub-seconds belong to the date time with the same suffix.
+const (
+	exifTagDateTime            = 0x0132
+	exifTagExifIFD             = 0x8769
+	exifTagDateTimeOriginal    = 0x9003
+	exifTagDateTimeDigitized   = 0x9004
+	exifTagOffsetTime          = 0x9010
+	exifTagOffsetTimeOriginal  = 0x9011
+	exifTagOffsetTimeDigitized = 0x9012
+	exifTagSubSecTime          = 0x9290
+	exifTagSubSecTimeOriginal  = 0x9291
+	exifTagSubSecTimeDigitized = 0x9292
+)
+
+// exifTimes are the EXIF times in order of relevance, with the tags of their offset and sub-seconds.
+var exifTimes = []struct {
+	name                   string
+	tag, offsetTag, subSec uint16
+	offsetName             string
+}{
+	{"DateTimeOriginal", exifTagDateTimeOriginal, exifTagOffsetTimeOriginal, exifTagSubSecTimeOriginal, "OffsetTimeOriginal"},
+	{"DateTimeDigitized", exifTagDateTimeDigitized, exifTagOffsetTimeDigitized, exifTagSubSecTimeDigitized, "OffsetTimeDigitized"},
+	{"DateTime", exifTagDateTime, exifTagOffsetTime, exifTagSubSecTime, "OffsetTime"},
+}
+
+// exifLayout is the layout of EXIF date times, e.g. "2019:01:25 21:51:38".
+const exifLayout = "2006:01:02 15:04:05"
+
+// ReadEXIF returns the DateTimeOriginal, DateTimeDigitized and DateTime of a JPEG or TIFF image,
+// including the sub-seconds. Without the OffsetTime tags (EXIF 2.31), the times have no timezone and are
+// interpreted in the location. Only the EXIF data is read, not the image.
+func ReadEXIF(r io.ReaderAt, size int64, loc *time.Location) ([]Field, error) {
+	tiff, tiffSize, err := exifTIFF(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := readTIFF(tiff, tiffSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []Field
+	for _, exifTime := range exifTimes {
+		value, ok := tags[exifTime.tag]
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		// unknown parts are replaced by spaces or zeros
+		if strings.Trim(value, " :0") == "" {
+			fields = append(fields, Field{Name: exifTime.name, Note: "unknown date"})
+			continue
+		}
+
+		field := Field{Name: exifTime.name}
+		location := loc
+		if offset, ok := tags[exifTime.offsetTag]; ok {
+			zone, err := time.Parse("-07:00", strings.TrimSpace(offset))
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid %v '%v'", ErrMetadata, exifTime.offsetName, offset)
+			}
+			_, seconds := zone.Zone()
+			location = time.FixedZone("", seconds)
+		} else {
+			field.Note = fmt.Sprintf("without timezone (no %v)", exifTime.offsetName)
+		}
+
+		t, err := time.ParseInLocation(exifLayout, value, location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %v '%v'", ErrMetadata, exifTime.name, value)
+		}
+		if subSec := strings.TrimSpace(tags[exifTime.subSec]); subSec != "" {
+			// the digits are the fraction, e.g. "05" is 50ms
+			if fraction, err := time.ParseDuration("0." + subSec + "s"); err == nil {
+				t = t.Add(fraction)
+			}
+		}
+		field.Time = t
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// isEXIFTimeTag reports whether the tag is one of the times of exifTimes, or their offset or sub-seconds.
+func isEXIFTimeTag(tag uint16) bool {
+	for _, exifTime := range exifTimes {
+		if tag == exifTime.tag || tag == exifTime.offsetTag || tag == exifTime.subSec {
+			return true
+		}
+	}
+	return false
+}
+
+// readEXIFAt reads n bytes at the offset, which have to be within the size.
+func readEXIFAt(r io.ReaderAt, size, offset, n int64) ([]byte, error) {
+	if offset < 0 || n < 0 || offset+n > size {
+		return nil, fmt.Errorf("%w: EXIF data is truncated", ErrMetadata)
+	}
+	b := make([]byte, n)
+	if read, err := r.ReadAt(b, offset); read < len(b) {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: EXIF data is truncated", ErrMetadata)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrMetadata, err)
+	}
+	return b, nil
+}
+
+// exifTIFF returns the TIFF structure of the APP1 segment of JPEG images and its size, or the TIFF image itself.
+func exifTIFF(r io.ReaderAt, size int64) (io.ReaderAt, int64, error) {
+	if magic, err := readEXIFAt(r, size, 0, 2); err != nil || !bytes.Equal(magic, []byte{0xff, 0xd8}) {
+		return r, size, nil
+	}
+
+	// segments: marker (2), length including itself (2), payload
+	for i := int64(2); i+4 <= size; {
+		segment, err := readEXIFAt(r, size, i, 4)
+		if err != nil {
+			return nil, 0, err
+		}
+		if segment[0] != 0xff {
+			break
+		}
+		marker := segment[1]
+		// start of scan, the image data follows
+		if marker == 0xda || marker == 0xd9 {
+			break
+		}
+		length := int64(binary.BigEndian.Uint16(segment[2:]))
+		if length < 2 || i+2+length > size {
+			break
+		}
+		if marker == 0xe1 && length >= 8 {
+			id, err := readEXIFAt(r, size, i+4, 6)
+			if err != nil {
+				return nil, 0, err
+			}
+			if bytes.Equal(id, []byte("Exif\x00\x00")) {
+				return io.NewSectionReader(r, i+10, length-8), length - 8, nil
+			}
+		}
+		i += 2 + length
+	}
+	return nil, 0, fmt.Errorf("%w: no EXIF data found", ErrMetadata)
+}
+
+// readTIFF returns the ASCII values of the times in the first IFD and the EXIF IFD by tag.
+func readTIFF(tiff io.ReaderAt, size int64) (map[uint16]string, error) {
+	header, err := readEXIFAt(tiff, size, 0, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	var order binary.ByteOrder
+	switch string(header[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("%w: invalid TIFF byte order '%q'", ErrMetadata, header[:2])
+	}
+
+	tags := map[uint16]string{}
+	exifOffset, err := readIFD(tiff, size, order, order.Uint32(header[4:]), tags)
+	if err != nil {
+		return nil, err
+	}
+	if exifOffset != 0 {
+		if _, err := readIFD(tiff, size, order, exifOffset, tags); err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// readIFD adds the ASCII values of the times in the IFD at the offset to the tags and returns the offset of the EXIF IFD.
+func readIFD(tiff io.ReaderAt, size int64, order binary.ByteOrder, offset uint32, tags map[uint16]string) (uint32, error) {
+	const (
+		typeASCII = 2
+		typeLong  = 4
+		entrySize = 12
+	)
+
+	countBytes, err := readEXIFAt(tiff, size, int64(offset), 2)
+	if err != nil {
+		return 0, err
+	}
+	count := int64(order.Uint16(countBytes))
+	entries, err := readEXIFAt(tiff, size, int64(offset)+2, count*entrySize)
+	if err != nil {
+		return 0, err
+	}
+
+	var exifOffset uint32
+	for i := range count {
+		entry := entries[i*entrySize : (i+1)*entrySize]
+		tag, typ, n := order.Uint16(entry), order.Uint16(entry[2:]), int64(order.Uint32(entry[4:]))
+
+		switch {
+		case tag == exifTagExifIFD && typ == typeLong:
+			exifOffset = order.Uint32(entry[8:])
+		case typ == typeASCII && isEXIFTimeTag(tag):
+			// values of up to 4 bytes are stored in the entry, longer values at the offset
+			value := entry[8:min(8+n, entrySize)]
+			if n > 4 {
+				if value, err = readEXIFAt(tiff, size, int64(order.Uint32(entry[8:])), n); err != nil {
+					return 0, err
+				}
+			}
+			tags[tag] = string(bytes.TrimRight(value, "\x00"))
+		}
+	}
+	return exifOffset, nil
+}
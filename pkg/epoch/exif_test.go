@@ -0,0 +1,180 @@
+package epoch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+type testByteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// testTIFF creates a TIFF structure with the ASCII tags in the first IFD and the EXIF tags in the EXIF IFD.
+func testTIFF(order testByteOrder, tags, exifTags map[uint16]string) []byte {
+	const entrySize = 12
+
+	var data []byte
+	if order == binary.LittleEndian {
+		data = []byte("II*\x00")
+	} else {
+		data = []byte("MM\x00*")
+	}
+	data = order.AppendUint32(data, 8)
+
+	// both IFDs are followed by their values
+	writeIFD := func(tags map[uint16]string, exifOffset uint32) {
+		keys := make([]int, 0, len(tags))
+		for tag := range tags {
+			keys = append(keys, int(tag))
+		}
+		sort.Ints(keys)
+
+		entries := len(keys)
+		if exifOffset != 0 {
+			entries++
+		}
+		valueOffset := uint32(len(data) + 2 + entries*entrySize + 4)
+
+		var values []byte
+		data = order.AppendUint16(data, uint16(entries))
+		for _, tag := range keys {
+			value := append([]byte(tags[uint16(tag)]), 0)
+			data = order.AppendUint16(data, uint16(tag))
+			data = order.AppendUint16(data, 2)
+			data = order.AppendUint32(data, uint32(len(value)))
+			if len(value) <= 4 {
+				data = append(data, append(value, make([]byte, 4-len(value))...)...)
+				continue
+			}
+			data = order.AppendUint32(data, valueOffset+uint32(len(values)))
+			values = append(values, value...)
+		}
+		if exifOffset != 0 {
+			data = order.AppendUint16(data, exifTagExifIFD)
+			data = order.AppendUint16(data, 4)
+			data = order.AppendUint32(data, 1)
+			data = order.AppendUint32(data, exifOffset)
+		}
+		data = order.AppendUint32(data, 0) // no next IFD
+		data = append(data, values...)
+	}
+
+	// the EXIF IFD starts after the first IFD and its values
+	first := testTIFFSize(tags, len(exifTags) > 0)
+	exifOffset := uint32(0)
+	if len(exifTags) > 0 {
+		exifOffset = uint32(8 + first)
+	}
+	writeIFD(tags, exifOffset)
+	if len(exifTags) > 0 {
+		writeIFD(exifTags, 0)
+	}
+	return data
+}
+
+// testTIFFSize returns the size of an IFD with its values.
+func testTIFFSize(tags map[uint16]string, exifPointer bool) int {
+	entries := len(tags)
+	if exifPointer {
+		entries++
+	}
+	size := 2 + entries*12 + 4
+	for _, value := range tags {
+		if len(value)+1 > 4 {
+			size += len(value) + 1
+		}
+	}
+	return size
+}
+
+// testJPEG wraps the TIFF structure into the APP1 segment of a JPEG image after an APP0 segment.
+func testJPEG(tiff []byte) []byte {
+	data := []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x04, 'J', 'F'}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	data = append(data, 0xff, 0xe1)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(payload)+2))
+	data = append(data, payload...)
+	return append(data, 0xff, 0xda)
+}
+
+func TestReadEXIF(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	equal(t, err, nil)
+
+	exifTags := map[uint16]string{
+		exifTagDateTimeOriginal:   "2019:01:25 21:51:38",
+		exifTagOffsetTimeOriginal: "+09:00",
+		exifTagSubSecTimeOriginal: "05",
+		exifTagDateTimeDigitized:  "2019:01:25 21:51:38",
+	}
+
+	testCases := []struct {
+		description string
+		given       []byte
+		expected    []Field
+		expectedErr error
+	}{
+		{
+			description: "jpeg",
+			given:       testJPEG(testTIFF(binary.BigEndian, map[uint16]string{exifTagDateTime: "2019:01:26 10:00:00", exifTagOffsetTime: "+01:00"}, exifTags)),
+			expected: []Field{
+				{Name: "DateTimeOriginal", Time: time.Date(2019, 1, 25, 21, 51, 38, 50_000_000, time.FixedZone("", 9*3600))},
+				{Name: "DateTimeDigitized", Time: time.Date(2019, 1, 25, 21, 51, 38, 0, berlin), Note: "without timezone (no OffsetTimeDigitized)"},
+				{Name: "DateTime", Time: time.Date(2019, 1, 26, 10, 0, 0, 0, time.FixedZone("", 3600))},
+			},
+		},
+		{
+			description: "tiff",
+			given:       testTIFF(binary.LittleEndian, map[uint16]string{exifTagDateTime: "2019:01:26 10:00:00"}, nil),
+			expected:    []Field{{Name: "DateTime", Time: time.Date(2019, 1, 26, 10, 0, 0, 0, berlin), Note: "without timezone (no OffsetTime)"}},
+		},
+		{
+			description: "unknown date",
+			given:       testTIFF(binary.LittleEndian, nil, map[uint16]string{exifTagDateTimeOriginal: "    :  :     :  :  "}),
+			expected:    []Field{{Name: "DateTimeOriginal", Note: "unknown date"}},
+		},
+		{
+			description: "no times",
+			given:       testTIFF(binary.LittleEndian, map[uint16]string{0x010f: "Camera"}, nil),
+		},
+		{
+			description: "invalid date",
+			given:       testTIFF(binary.LittleEndian, map[uint16]string{exifTagDateTime: "2019-01-26 10:00:00"}, nil),
+			expectedErr: errors.New("failed to read metadata: invalid DateTime '2019-01-26 10:00:00'"),
+		},
+		{
+			description: "invalid offset",
+			given:       testTIFF(binary.LittleEndian, map[uint16]string{exifTagDateTime: "2019:01:26 10:00:00", exifTagOffsetTime: "CET"}, nil),
+			expectedErr: errors.New("failed to read metadata: invalid OffsetTime 'CET'"),
+		},
+		{
+			description: "jpeg without exif",
+			given:       []byte{0xff, 0xd8, 0xff, 0xda},
+			expectedErr: errors.New("failed to read metadata: no EXIF data found"),
+		},
+		{
+			description: "truncated",
+			given:       testTIFF(binary.LittleEndian, map[uint16]string{exifTagDateTime: "2019:01:26 10:00:00"}, nil)[:20],
+			expectedErr: errors.New("failed to read metadata: EXIF data is truncated"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := ReadEXIF(bytes.NewReader(tt.given), int64(len(tt.given)), berlin)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				equal(t, errors.Is(err, ErrMetadata), true)
+				return
+			} else if tt.expectedErr != nil {
+				t.Fatalf("expected error %q", tt.expectedErr)
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
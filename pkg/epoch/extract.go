@@ -0,0 +1,136 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrNoTime is returned when a text doesn't contain any time.
+var ErrNoTime = errors.New("no time found")
+
+// maxMatchLength is the maximum length in bytes of times found in texts.
+const maxMatchLength = 80
+
+// Match is a time found in a text.
+type Match struct {
+	// Start and End are the byte offsets of the time in the text, Text[Start:End].
+	Start, End int
+	Text       string
+	// Layout is the name of the matching layout (see Parser.Parse).
+	Layout string
+	Time   time.Time
+}
+
+// Extract returns all non-overlapping times in the text, e.g. in a log line or an email header.
+// Times start and end at word boundaries, the longest time at the leftmost position is used.
+// Ambiguous numeric dates (see ParseNumericDate) are skipped.
+func (p Parser) Extract(text string, tz *time.Location) []Match {
+	matches, _ := p.extract(text, tz, false)
+	return matches
+}
+
+// ExtractFirst returns the first time in the text (see Extract). It returns ErrNoTime when
+// the text doesn't contain a time or an *AmbiguousDateError when it only contains ambiguous dates.
+func (p Parser) ExtractFirst(text string, tz *time.Location) (Match, error) {
+	matches, err := p.extract(text, tz, true)
+	if len(matches) > 0 {
+		return matches[0], nil
+	}
+	if err != nil {
+		return Match{}, err
+	}
+	return Match{}, fmt.Errorf("%w in '%v'", ErrNoTime, text)
+}
+
+// extract returns the matches and the first ambiguity error.
+func (p Parser) extract(text string, tz *time.Location, first bool) ([]Match, error) {
+	var (
+		matches   []Match
+		ambiguous error
+	)
+
+	for start := 0; start < len(text); {
+		if !isWordStart(text, start) {
+			_, size := utf8.DecodeRuneInString(text[start:])
+			start += size
+			continue
+		}
+
+		match, err := p.longestMatch(text, start, tz)
+		if err != nil && ambiguous == nil {
+			ambiguous = err
+		}
+		if match == nil {
+			_, size := utf8.DecodeRuneInString(text[start:])
+			start += size
+			continue
+		}
+
+		matches = append(matches, *match)
+		if first {
+			break
+		}
+		start = match.End
+	}
+
+	return matches, ambiguous
+}
+
+// longestMatch returns the longest time starting at start or the first ambiguity error.
+func (p Parser) longestMatch(text string, start int, tz *time.Location) (*Match, error) {
+	var ambiguous error
+
+	limit := min(len(text), start+maxMatchLength)
+	for end := limit; end > start; end-- {
+		if !isWordEnd(text, end) {
+			continue
+		}
+
+		t, layout, _, err := p.parse(text[start:end], tz)
+		if err == nil {
+			return &Match{Start: start, End: end, Text: text[start:end], Layout: layout, Time: t}, nil
+		}
+		var ambiguousErr *AmbiguousDateError
+		if ambiguous == nil && errors.As(err, &ambiguousErr) {
+			ambiguous = err
+		}
+	}
+
+	return nil, ambiguous
+}
+
+// isWordStart reports whether a letter or digit starts at i which doesn't continue a word.
+func isWordStart(text string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	if !isWordRune(r) {
+		return false
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:i])
+	return i == 0 || !joins(prev, r)
+}
+
+// isWordEnd reports whether a letter or digit ends at i which isn't continued by the next rune.
+func isWordEnd(text string, i int) bool {
+	if i < len(text) && !utf8.RuneStart(text[i]) {
+		return false
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:i])
+	if !isWordRune(prev) {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(text[i:])
+	return i == len(text) || !joins(prev, next)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// joins reports whether the runes belong to the same word.
+// Scripts without spaces between words, such as Japanese, have boundaries at each rune.
+func joins(a, b rune) bool {
+	return isWordRune(a) && isWordRune(b) && !unicode.In(a, unicode.Han, unicode.Hiragana, unicode.Katakana) && !unicode.In(b, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
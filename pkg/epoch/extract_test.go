@@ -0,0 +1,80 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExtract(t *testing.T) {
+	ja, err := LookupLocale("ja")
+	equal(t, err, nil)
+
+	testCases := []struct {
+		description string
+		parser      Parser
+		text        string
+		expected    []Match
+	}{
+		{
+			description: "log line",
+			text:        "[2019-01-25 21:51:38] ERROR connection lost",
+			expected:    []Match{{Start: 1, End: 20, Text: "2019-01-25 21:51:38", Layout: TimeFormatSimple, Time: time.Date(2019, 1, 25, 21, 51, 38, 0, time.UTC)}},
+		},
+		{
+			description: "email header",
+			text:        "Date: Fri, 25 Jan 2019 21:51:38 +0100",
+			expected:    []Match{{Start: 6, End: 37, Text: "Fri, 25 Jan 2019 21:51:38 +0100", Layout: time.RFC1123Z, Time: time.Date(2019, 1, 25, 21, 51, 38, 0, time.FixedZone("", 3600))}},
+		},
+		{
+			description: "all",
+			text:        "from 2024-01-01T00:00:00Z to 25.01.2024.",
+			expected: []Match{
+				{Start: 5, End: 25, Text: "2024-01-01T00:00:00Z", Layout: time.RFC3339, Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{Start: 29, End: 39, Text: "25.01.2024", Layout: "02.01.2006", Time: time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			description: "word boundaries",
+			text:        "id12/01/2024 v3.1.24x",
+		},
+		{
+			description: "ambiguous date skipped",
+			text:        "03/04/2024 or 2024-04-03",
+			expected:    []Match{{Start: 14, End: 24, Text: "2024-04-03", Layout: "2006-01-02", Time: time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			description: "locale without spaces",
+			parser:      Parser{Locale: &ja},
+			text:        "発行日は2019年1月25日です",
+			expected:    []Match{{Start: 12, End: 28, Text: "2019年1月25日", Layout: "2006年1月2日", Time: time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC)}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got := tt.parser.Extract(tt.text, time.UTC)
+			equal(t, got, tt.expected)
+			for _, match := range got {
+				equal(t, tt.text[match.Start:match.End], match.Text)
+			}
+		})
+	}
+}
+
+func TestExtractFirst(t *testing.T) {
+	match, err := Parser{}.ExtractFirst("expires 2024-12-31 and 2025-12-31", time.UTC)
+	equal(t, err, nil)
+	equal(t, match.Time, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+
+	_, err = Parser{}.ExtractFirst("no time", time.UTC)
+	equalError(t, err, errors.New("no time found in 'no time'"))
+
+	_, err = Parser{}.ExtractFirst("on 03/04/2024", time.UTC)
+	var ambiguous *AmbiguousDateError
+	equal(t, errors.As(err, &ambiguous), true)
+
+	match, err = Parser{DateOrder: DateOrderMDY}.ExtractFirst("on 03/04/2024", time.UTC)
+	equal(t, err, nil)
+	equal(t, match.Time, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+}
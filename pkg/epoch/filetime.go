@@ -0,0 +1,53 @@
+package epoch
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// FileTimes are the timestamps of a file with the precision of the filesystem, zero when the platform doesn't provide them.
+type FileTimes struct {
+	Path string
+	// Modified is the time the content was modified (mtime).
+	Modified time.Time
+	// Accessed is the time the content was read (atime), often only updated once a day (relatime).
+	Accessed time.Time
+	// Changed is the time the content or the metadata, e.g. the permissions, was changed (ctime).
+	Changed time.Time
+	// Birth is the creation time of the file (statx on Linux).
+	Birth time.Time
+}
+
+// Fields returns the times named after the path, e.g. "main.go mtime".
+func (f FileTimes) Fields() []Field {
+	fields := []Field{
+		{Name: "mtime", Time: f.Modified},
+		{Name: "atime", Time: f.Accessed},
+		{Name: "ctime", Time: f.Changed},
+		{Name: "birth", Time: f.Birth},
+	}
+	for i := range fields {
+		fields[i].Name = fmt.Sprintf("%v %v", f.Path, fields[i].Name)
+		if fields[i].Time.IsZero() {
+			fields[i].Note = "not supported by the filesystem or platform"
+		}
+	}
+	return fields
+}
+
+// StatFile returns the timestamps of the file, symbolic links are followed.
+func StatFile(path string) (FileTimes, error) {
+	times, err := statTimes(path)
+	if err != nil {
+		return FileTimes{}, err
+	}
+	times.Path = path
+	return times, nil
+}
+
+// SetFileTimes sets the access and modification times of the file.
+// The change and birth times can't be set, the change time becomes the current time.
+func SetFileTimes(path string, accessed, modified time.Time) error {
+	return os.Chtimes(path, accessed, modified)
+}
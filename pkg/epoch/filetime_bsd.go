@@ -0,0 +1,27 @@
+//go:build darwin || freebsd || netbsd
+
+package epoch
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func statTimes(path string) (FileTimes, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return FileTimes{}, &os.PathError{Op: "stat", Path: path, Err: err}
+	}
+
+	times := FileTimes{
+		Modified: time.Unix(int64(stat.Mtimespec.Sec), int64(stat.Mtimespec.Nsec)),
+		Accessed: time.Unix(int64(stat.Atimespec.Sec), int64(stat.Atimespec.Nsec)),
+		Changed:  time.Unix(int64(stat.Ctimespec.Sec), int64(stat.Ctimespec.Nsec)),
+	}
+	// filesystems without birth times report -1 or 0
+	if stat.Birthtimespec.Sec > 0 {
+		times.Birth = time.Unix(int64(stat.Birthtimespec.Sec), int64(stat.Birthtimespec.Nsec))
+	}
+	return times, nil
+}
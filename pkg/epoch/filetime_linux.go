@@ -0,0 +1,111 @@
+package epoch
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// sysStatx are the numbers of the statx system call (Linux 4.11), which isn't part of the syscall package.
+var sysStatx = map[string]uintptr{
+	"386":      383,
+	"amd64":    332,
+	"arm":      397,
+	"arm64":    291,
+	"loong64":  291,
+	"ppc64":    383,
+	"ppc64le":  383,
+	"riscv64":  291,
+	"s390x":    379,
+	"mips":     4366,
+	"mipsle":   4366,
+	"mips64":   5326,
+	"mips64le": 5326,
+}
+
+const (
+	atFdCwd    = -100
+	statxBasic = 0x7ff
+	statxBtime = 0x800
+)
+
+type statxTimestamp struct {
+	Sec  int64
+	Nsec uint32
+	_    int32
+}
+
+// statx is struct statx of linux/stat.h.
+type statx struct {
+	Mask           uint32
+	Blksize        uint32
+	Attributes     uint64
+	Nlink          uint32
+	UID            uint32
+	GID            uint32
+	Mode           uint16
+	_              uint16
+	Ino            uint64
+	Size           uint64
+	Blocks         uint64
+	AttributesMask uint64
+	Atime          statxTimestamp
+	Btime          statxTimestamp
+	Ctime          statxTimestamp
+	Mtime          statxTimestamp
+	RdevMajor      uint32
+	RdevMinor      uint32
+	DevMajor       uint32
+	DevMinor       uint32
+	_              [14]uint64
+}
+
+func statTimes(path string) (FileTimes, error) {
+	if times, ok := statxTimes(path); ok {
+		return times, nil
+	}
+
+	// statx isn't available, e.g. on old kernels or blocked by seccomp
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return FileTimes{}, &os.PathError{Op: "stat", Path: path, Err: err}
+	}
+	return FileTimes{
+		Modified: time.Unix(int64(stat.Mtim.Sec), int64(stat.Mtim.Nsec)),
+		Accessed: time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec)),
+		Changed:  time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec)),
+	}, nil
+}
+
+// statxTimes returns the times including the birth time, false when statx fails.
+// Errors such as missing files are reported by the fallback.
+func statxTimes(path string) (FileTimes, bool) {
+	trap, ok := sysStatx[runtime.GOARCH]
+	if !ok {
+		return FileTimes{}, false
+	}
+	name, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return FileTimes{}, false
+	}
+
+	var stx statx
+	dirfd := atFdCwd
+	_, _, errno := syscall.Syscall6(trap, uintptr(dirfd), uintptr(unsafe.Pointer(name)), 0, statxBasic|statxBtime, uintptr(unsafe.Pointer(&stx)), 0)
+	if errno != 0 {
+		return FileTimes{}, false
+	}
+
+	times := FileTimes{
+		Modified: time.Unix(stx.Mtime.Sec, int64(stx.Mtime.Nsec)),
+		Accessed: time.Unix(stx.Atime.Sec, int64(stx.Atime.Nsec)),
+		Changed:  time.Unix(stx.Ctime.Sec, int64(stx.Ctime.Nsec)),
+	}
+	// not all filesystems store the birth time, e.g. tmpfs before Linux 5.18
+	if stx.Mask&statxBtime != 0 {
+		times.Birth = time.Unix(stx.Btime.Sec, int64(stx.Btime.Nsec))
+	}
+	return times, true
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !windows
+
+package epoch
+
+import "os"
+
+// statTimes only returns the modification time, the other times aren't portable.
+func statTimes(path string) (FileTimes, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileTimes{}, err
+	}
+	return FileTimes{Modified: info.ModTime()}, nil
+}
@@ -0,0 +1,40 @@
+package epoch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	equal(t, os.WriteFile(path, nil, 0o600), nil)
+
+	accessed := time.Date(2024, 1, 1, 0, 0, 0, 123456789, time.UTC)
+	modified := time.Date(2024, 2, 29, 12, 30, 0, 987654321, time.UTC)
+	equal(t, SetFileTimes(path, accessed, modified), nil)
+
+	got, err := StatFile(path)
+	equal(t, err, nil)
+	equal(t, got.Path, path)
+	// filesystems round to their precision, e.g. 100ns on Windows and 1s on HFS+
+	if got.Modified.Sub(modified).Abs() >= time.Second {
+		t.Fatalf("got mtime %v, want %v", got.Modified, modified)
+	}
+
+	_, err = StatFile(filepath.Join(t.TempDir(), "missing"))
+	equal(t, os.IsNotExist(err), true)
+}
+
+func TestFileTimesFields(t *testing.T) {
+	modified := time.Date(2024, 2, 29, 12, 30, 0, 987654321, time.UTC)
+	times := FileTimes{Path: "main.go", Modified: modified, Accessed: modified, Changed: modified}
+
+	equal(t, times.Fields(), []Field{
+		{Name: "main.go mtime", Time: modified},
+		{Name: "main.go atime", Time: modified},
+		{Name: "main.go ctime", Time: modified},
+		{Name: "main.go birth", Note: "not supported by the filesystem or platform"},
+	})
+}
@@ -0,0 +1,25 @@
+package epoch
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// statTimes returns the times with a precision of 100ns, Windows doesn't have a change time.
+func statTimes(path string) (FileTimes, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileTimes{}, err
+	}
+
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return FileTimes{Modified: info.ModTime()}, nil
+	}
+	return FileTimes{
+		Modified: time.Unix(0, data.LastWriteTime.Nanoseconds()),
+		Accessed: time.Unix(0, data.LastAccessTime.Nanoseconds()),
+		Birth:    time.Unix(0, data.CreationTime.Nanoseconds()),
+	}, nil
+}
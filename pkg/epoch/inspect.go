@@ -0,0 +1,271 @@
+package epoch
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInspect is returned when an input can't be inspected.
+var ErrInspect = errors.New("failed to inspect input")
+
+// Field is a time found by an inspector, e.g. the "exp" claim of a JWT.
+type Field struct {
+	// Name is the name of the claim, attribute or header, e.g. "exp", "Max-Age" or "Last-Modified".
+	Name string
+	// Time is zero when the value isn't a time, see Note.
+	Time time.Time
+	// Note describes peculiarities of the value, e.g. "invalid date, already expired".
+	Note string
+}
+
+// jwtClaims are the time claims of JWTs (RFC 7519 and OpenID Connect) in the order they are listed.
+var jwtClaims = []string{"iat", "auth_time", "nbf", "exp"}
+
+// httpDateLayouts are the layouts of HTTP dates (RFC 9110), including the Netscape format of cookies.
+var httpDateLayouts = []string{TimeFormatHTTP, time.RFC850, time.ANSIC, "Mon, 02-Jan-2006 15:04:05 MST"}
+
+// httpDateHeaders are the headers with HTTP dates.
+var httpDateHeaders = []string{"Date", "Expires", "Last-Modified", "Retry-After"}
+
+// Inspect detects whether the input is a JWT, a journal entry, a Set-Cookie line or HTTP headers and returns their times.
+// Durations such as Max-Age and Retry-After are relative to now.
+func Inspect(input string, now time.Time) ([]Field, error) {
+	input = strings.TrimSpace(input)
+
+	if IsJournalEntry(input) {
+		entry, err := ParseJournalEntry(input)
+		if err != nil {
+			return nil, err
+		}
+		return entry.Fields(), nil
+	}
+
+	token, isBearer := cutBearer(input)
+	if isBearer || isJWT(token) {
+		return InspectJWT(token)
+	}
+
+	// header names can't contain the separators of cookies
+	name, value, _ := strings.Cut(input, ":")
+	if !strings.Contains(input, "\n") {
+		switch {
+		case strings.EqualFold(strings.TrimSpace(name), "Set-Cookie"):
+			return InspectCookie(value, now)
+		case strings.ContainsAny(name, "=;"):
+			return InspectCookie(input, now)
+		}
+	}
+
+	return InspectHeaders(input, now)
+}
+
+// cutBearer removes an authorization header or scheme, e.g. "Authorization: Bearer <token>".
+func cutBearer(input string) (string, bool) {
+	if name, value, found := strings.Cut(input, ":"); found && strings.EqualFold(strings.TrimSpace(name), "Authorization") {
+		input = strings.TrimSpace(value)
+	}
+	if scheme, token, found := strings.Cut(input, " "); found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token), true
+	}
+	return input, false
+}
+
+// isJWT reports whether the input has three parts and the first part is a JSON object.
+func isJWT(input string) bool {
+	parts := strings.Split(input, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	header, err := decodeSegment(parts[0])
+	return err == nil && json.Valid(header) && bytes.HasPrefix(bytes.TrimSpace(header), []byte("{"))
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+}
+
+// InspectJWT returns the time claims of the JWT's payload (iat, auth_time, nbf and exp).
+// The signature isn't verified.
+func InspectJWT(token string) ([]Field, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: a JWT has 3 parts separated by '.', got %v", ErrInspect, len(parts))
+	}
+
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode the JWT payload: %v", ErrInspect, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var claims map[string]any
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode the JWT claims: %v", ErrInspect, err)
+	}
+
+	var fields []Field
+	for _, name := range jwtClaims {
+		value, ok := claims[name]
+		if !ok {
+			continue
+		}
+		number, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%w: claim '%v' is no number: %v", ErrInspect, name, value)
+		}
+		t, err := jwtNumericDate(number)
+		if err != nil {
+			return nil, fmt.Errorf("%w: claim '%v': %v", ErrInspect, name, err)
+		}
+		fields = append(fields, Field{Name: name, Time: t})
+	}
+	return fields, nil
+}
+
+// jwtNumericDate converts seconds since the epoch, which might have fractions (RFC 7519).
+func jwtNumericDate(number json.Number) (time.Time, error) {
+	if seconds, err := number.Int64(); err == nil {
+		return ParseTimestamp(seconds, UnitSeconds)
+	}
+
+	f, err := number.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if f < math.MinInt64/1e9 || f > math.MaxInt64/1e9 {
+		return time.Time{}, fmt.Errorf("%v %w", number, ErrOutOfRange)
+	}
+	// split the seconds, multiplying them with 1e9 loses the precision of the fraction
+	seconds, fraction := math.Modf(f)
+	return time.Unix(int64(seconds), int64(math.Round(fraction*1e9))), nil
+}
+
+// InspectCookie returns the Expires and Max-Age times of a Set-Cookie value, e.g.
+// "id=a3fWa; Expires=Wed, 21 Oct 2015 07:28:00 GMT; Max-Age=2592000". Max-Age is relative to now.
+// Malformed times are reported as fields with a note.
+func InspectCookie(cookie string, now time.Time) ([]Field, error) {
+	var fields []Field
+	for _, attribute := range strings.Split(cookie, ";")[1:] {
+		name, value, _ := strings.Cut(attribute, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+
+		switch {
+		case strings.EqualFold(name, "Expires"):
+			fields = append(fields, expiresField("Expires", value))
+		case strings.EqualFold(name, "Max-Age"):
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				// RFC 6265: ignore the attribute
+				fields = append(fields, Field{Name: "Max-Age", Note: fmt.Sprintf("invalid Max-Age '%v', ignored", value)})
+				continue
+			}
+			if seconds <= 0 {
+				// RFC 6265: expire immediately
+				fields = append(fields, Field{Name: "Max-Age", Time: now, Note: "expires immediately"})
+				continue
+			}
+			t, err := addSeconds(now, seconds)
+			if err != nil {
+				fields = append(fields, Field{Name: "Max-Age", Note: err.Error()})
+				continue
+			}
+			fields = append(fields, Field{Name: "Max-Age", Time: t})
+		}
+	}
+	return fields, nil
+}
+
+// addSeconds returns the time the seconds after now, a time.Duration only holds about 292 years.
+func addSeconds(now time.Time, seconds int64) (time.Time, error) {
+	unix := now.Unix() + seconds
+	// the sum overflowed or the time can't represent it
+	if (unix > now.Unix()) != (seconds > 0) || time.Unix(unix, 0).Unix() != unix {
+		return time.Time{}, fmt.Errorf("%v seconds %w", seconds, ErrOutOfRange)
+	}
+	return time.Unix(unix, int64(now.Nanosecond())).In(now.Location()), nil
+}
+
+// expiresField returns the Expires field, invalid dates mean already expired (RFC 9111).
+func expiresField(name, value string) Field {
+	t, err := parseHTTPDate(value)
+	if err != nil {
+		return Field{Name: name, Note: fmt.Sprintf("invalid date '%v', already expired", value)}
+	}
+	return Field{Name: name, Time: t}
+}
+
+func parseHTTPDate(value string) (time.Time, error) {
+	for _, layout := range httpDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid HTTP date '%v'", ErrInspect, value)
+}
+
+// InspectHeaders returns the times of raw HTTP headers, one "Name: value" per line: Date, Expires, Last-Modified,
+// Retry-After (relative to now when it's a delay in seconds) and the Expires and Max-Age of Set-Cookie headers.
+// Status lines and other headers are ignored, malformed times are reported as fields with a note.
+func InspectHeaders(headers string, now time.Time) ([]Field, error) {
+	var fields []Field
+	for _, line := range strings.Split(headers, "\n") {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+
+		if strings.EqualFold(name, "Set-Cookie") {
+			cookie, err := InspectCookie(value, now)
+			if err != nil {
+				return nil, err
+			}
+			cookieName, _, _ := strings.Cut(value, "=")
+			for _, field := range cookie {
+				field.Name = fmt.Sprintf("Set-Cookie %v %v", strings.TrimSpace(cookieName), field.Name)
+				fields = append(fields, field)
+			}
+			continue
+		}
+
+		for _, header := range httpDateHeaders {
+			if !strings.EqualFold(name, header) {
+				continue
+			}
+
+			switch header {
+			case "Expires":
+				fields = append(fields, expiresField(header, value))
+				continue
+			case "Retry-After":
+				if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+					t, err := addSeconds(now, seconds)
+					if err != nil {
+						fields = append(fields, Field{Name: header, Note: err.Error()})
+						continue
+					}
+					fields = append(fields, Field{Name: header, Time: t})
+					continue
+				}
+			}
+
+			// like Expires, a malformed header doesn't hide the others
+			t, err := parseHTTPDate(value)
+			if err != nil {
+				fields = append(fields, Field{Name: header, Note: fmt.Sprintf("invalid date '%v'", value)})
+				continue
+			}
+			fields = append(fields, Field{Name: header, Time: t})
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,95 @@
+package epoch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrParseJournal is returned when a journal entry is invalid.
+var ErrParseJournal = errors.New("failed to parse journal entry")
+
+// JournalEntry is an entry of the systemd journal in the format of "journalctl -o json".
+type JournalEntry struct {
+	// Realtime is the __REALTIME_TIMESTAMP, when the journal received the entry.
+	Realtime time.Time
+	// SourceRealtime is the _SOURCE_REALTIME_TIMESTAMP, when the message was created, zero when missing.
+	SourceRealtime time.Time
+	Message        string
+}
+
+// IsJournalEntry reports whether the line looks like a journal entry, without parsing it.
+func IsJournalEntry(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "{") && strings.Contains(line, `"__REALTIME_TIMESTAMP"`)
+}
+
+// ParseJournalEntry parses a line of "journalctl -o json". The timestamps are microseconds since the epoch.
+func ParseJournalEntry(line string) (JournalEntry, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return JournalEntry{}, fmt.Errorf("%w: %v", ErrParseJournal, err)
+	}
+
+	var entry JournalEntry
+	realtime, ok := fields["__REALTIME_TIMESTAMP"]
+	if !ok {
+		return JournalEntry{}, fmt.Errorf("%w: missing __REALTIME_TIMESTAMP", ErrParseJournal)
+	}
+	var err error
+	if entry.Realtime, err = journalTimestamp("__REALTIME_TIMESTAMP", realtime); err != nil {
+		return JournalEntry{}, err
+	}
+	if source, ok := fields["_SOURCE_REALTIME_TIMESTAMP"]; ok {
+		if entry.SourceRealtime, err = journalTimestamp("_SOURCE_REALTIME_TIMESTAMP", source); err != nil {
+			return JournalEntry{}, err
+		}
+	}
+
+	if message, ok := fields["MESSAGE"]; ok {
+		entry.Message = journalString(message)
+	}
+	return entry, nil
+}
+
+// Fields returns the timestamps of the entry.
+func (e JournalEntry) Fields() []Field {
+	fields := []Field{{Name: "__REALTIME_TIMESTAMP", Time: e.Realtime}}
+	if !e.SourceRealtime.IsZero() {
+		fields = append(fields, Field{Name: "_SOURCE_REALTIME_TIMESTAMP", Time: e.SourceRealtime})
+	}
+	return fields
+}
+
+// journalTimestamp parses a timestamp in microseconds, journalctl encodes them as strings.
+func journalTimestamp(name string, value json.RawMessage) (time.Time, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		s = string(value)
+	}
+	us, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid %v '%v'", ErrParseJournal, name, s)
+	}
+	return ParseTimestamp(us, UnitMicroseconds)
+}
+
+// journalString decodes a field, which is an array of bytes for binary values and null for large values.
+func journalString(value json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s
+	}
+	var numbers []int
+	if err := json.Unmarshal(value, &numbers); err == nil {
+		var b []byte
+		for _, n := range numbers {
+			b = append(b, byte(n))
+		}
+		return string(b)
+	}
+	return ""
+}
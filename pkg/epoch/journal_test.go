@@ -0,0 +1,72 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseJournalEntry(t *testing.T) {
+	realtime := time.UnixMicro(1711706400123456)
+
+	testCases := []struct {
+		description string
+		given       string
+		expected    JournalEntry
+		expectedErr error
+	}{
+		{
+			description: "entry",
+			given:       `{"__CURSOR":"s=1","__REALTIME_TIMESTAMP":"1711706400123456","__MONOTONIC_TIMESTAMP":"5000","_SOURCE_REALTIME_TIMESTAMP":"1711706400120000","MESSAGE":"Started Daily Cleanup.","PRIORITY":"6"}`,
+			expected:    JournalEntry{Realtime: realtime, SourceRealtime: time.UnixMicro(1711706400120000), Message: "Started Daily Cleanup."},
+		},
+		{
+			description: "binary message",
+			given:       `{"__REALTIME_TIMESTAMP":"1711706400123456","MESSAGE":[104,105,10]}`,
+			expected:    JournalEntry{Realtime: realtime, Message: "hi\n"},
+		},
+		{
+			description: "large message",
+			given:       `{"__REALTIME_TIMESTAMP":"1711706400123456","MESSAGE":null}`,
+			expected:    JournalEntry{Realtime: realtime},
+		},
+		{
+			description: "missing timestamp",
+			given:       `{"MESSAGE":"hi"}`,
+			expectedErr: errors.New("failed to parse journal entry: missing __REALTIME_TIMESTAMP"),
+		},
+		{
+			description: "invalid timestamp",
+			given:       `{"__REALTIME_TIMESTAMP":"yesterday"}`,
+			expectedErr: errors.New("failed to parse journal entry: invalid __REALTIME_TIMESTAMP 'yesterday'"),
+		},
+		{
+			description: "no json",
+			given:       `__REALTIME_TIMESTAMP=1711706400123456`,
+			expectedErr: errors.New("failed to parse journal entry: invalid character '_' looking for beginning of value"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := ParseJournalEntry(tt.given)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				equal(t, errors.Is(err, ErrParseJournal), true)
+				return
+			} else if tt.expectedErr != nil {
+				t.Fatalf("expected error %q", tt.expectedErr)
+			}
+
+			equal(t, got.Realtime.Equal(tt.expected.Realtime), true)
+			equal(t, got.SourceRealtime.Equal(tt.expected.SourceRealtime), true)
+			equal(t, got.Message, tt.expected.Message)
+		})
+	}
+}
+
+func TestIsJournalEntry(t *testing.T) {
+	equal(t, IsJournalEntry(` {"__REALTIME_TIMESTAMP":"1711706400123456"}`), true)
+	equal(t, IsJournalEntry(`{"exp":1711706400}`), false)
+	equal(t, IsJournalEntry(`__REALTIME_TIMESTAMP=1711706400123456`), false)
+}
@@ -0,0 +1,349 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrUnsupportedToken is returned when a pattern contains a token which can't be expressed in a Go layout.
+var ErrUnsupportedToken = errors.New("token not supported by Go layouts")
+
+// layoutDialect describes the pattern syntax of another language or library.
+type layoutDialect struct {
+	name string
+	// tokens maps the tokens to Go layout elements, unsupported tokens map to "".
+	// Elements starting with "." are fractional seconds and have to follow a "." or "," in the pattern.
+	tokens map[string]string
+	// literal returns the literal text starting at position i when the pattern quotes or escapes it.
+	literal func(pattern string, i int) (text string, next int, ok bool, err error)
+	// strict rejects unknown letters instead of using them as literal text.
+	strict bool
+	// ordinal is a suffix forming another token, e.g. "o" for "Do" in moment.js.
+	ordinal string
+	// reserved are characters with an unsupported meaning, e.g. "[" for optional sections in Java.
+	reserved string
+}
+
+var (
+	javaDialect = layoutDialect{
+		name: "Java",
+		tokens: withFractions(map[string]string{
+			"G": "", "GG": "", "GGG": "", "GGGG": "",
+			"y": "2006", "yy": "06", "yyy": "2006", "yyyy": "2006",
+			"u": "2006", "uu": "06", "uuu": "2006", "uuuu": "2006",
+			"Y": "", "YY": "", "YYYY": "",
+			"M": "1", "MM": "01", "MMM": "Jan", "MMMM": "January", "MMMMM": "",
+			"L": "1", "LL": "01", "LLL": "Jan", "LLLL": "January", "LLLLL": "",
+			"d": "2", "dd": "02",
+			"D": "", "DD": "", "DDD": "002",
+			"E": "Mon", "EE": "Mon", "EEE": "Mon", "EEEE": "Monday", "EEEEE": "",
+			"a": "PM",
+			"H": "", "HH": "15",
+			"h": "3", "hh": "03",
+			"k": "", "kk": "", "K": "", "KK": "",
+			"m": "4", "mm": "04",
+			"s": "5", "ss": "05",
+			"n": "", "N": "", "A": "",
+			"z": "MST", "zz": "MST", "zzz": "MST", "zzzz": "",
+			"Z": "-0700", "ZZ": "-0700", "ZZZ": "-0700", "ZZZZ": "", "ZZZZZ": "Z07:00",
+			"X": "Z07", "XX": "Z0700", "XXX": "Z07:00", "XXXX": "Z070000", "XXXXX": "Z07:00:00",
+			"x": "-07", "xx": "-0700", "xxx": "-07:00", "xxxx": "-070000", "xxxxx": "-07:00:00",
+			"V": "", "VV": "", "O": "", "OOOO": "",
+			"Q": "", "QQ": "", "QQQ": "", "QQQQ": "", "q": "", "qq": "",
+			"w": "", "ww": "", "W": "", "e": "", "ee": "", "c": "", "F": "", "B": "",
+		}, "S", ".0"),
+		literal:  singleQuoted,
+		strict:   true,
+		reserved: "[]{}#",
+	}
+
+	dotNetDialect = layoutDialect{
+		name: ".NET",
+		tokens: withFractions(withFractions(map[string]string{
+			"y": "", "yy": "06", "yyy": "", "yyyy": "2006", "yyyyy": "",
+			"M": "1", "MM": "01", "MMM": "Jan", "MMMM": "January",
+			"d": "2", "dd": "02", "ddd": "Mon", "dddd": "Monday",
+			"g": "", "gg": "",
+			"h": "3", "hh": "03",
+			"H": "", "HH": "15",
+			"m": "4", "mm": "04",
+			"s": "5", "ss": "05",
+			"t": "", "tt": "PM",
+			"K": "Z07:00",
+			"z": "", "zz": "-07", "zzz": "-07:00",
+		}, "f", ".0"), "F", ".9"),
+		literal: dotNetLiteral,
+	}
+
+	momentDialect = layoutDialect{
+		name: "moment.js",
+		tokens: withFractions(map[string]string{
+			"Y": "", "YY": "06", "YYYY": "2006", "YYYYYY": "",
+			"y": "", "gg": "", "gggg": "", "GG": "", "GGGG": "",
+			"M": "1", "MM": "01", "MMM": "Jan", "MMMM": "January", "Mo": "",
+			"Q": "", "Qo": "",
+			"D": "2", "DD": "02", "Do": "",
+			"DDD": "", "DDDD": "002", "DDDo": "",
+			"d": "", "do": "", "dd": "", "ddd": "Mon", "dddd": "Monday",
+			"e": "", "E": "",
+			"w": "", "ww": "", "wo": "", "W": "", "WW": "", "Wo": "",
+			"A": "PM", "a": "pm",
+			"H": "", "HH": "15",
+			"h": "3", "hh": "03",
+			"k": "", "kk": "",
+			"m": "4", "mm": "04",
+			"s": "5", "ss": "05",
+			"z": "MST", "zz": "MST",
+			"Z": "-07:00", "ZZ": "-0700",
+			"X": "", "x": "",
+		}, "S", ".0"),
+		literal: bracketed,
+		ordinal: "o",
+	}
+
+	luxonDialect = layoutDialect{
+		name: "Luxon",
+		tokens: map[string]string{
+			"G": "", "GG": "", "GGGGG": "",
+			"y": "2006", "yy": "06", "yyyy": "2006", "yyyyyy": "",
+			"M": "1", "MM": "01", "MMM": "Jan", "MMMM": "January", "MMMMM": "",
+			"L": "1", "LL": "01", "LLL": "Jan", "LLLL": "January", "LLLLL": "",
+			"d": "2", "dd": "02",
+			"o": "", "ooo": "002",
+			"E": "", "EEE": "Mon", "EEEE": "Monday", "EEEEE": "",
+			"c": "", "ccc": "Mon", "cccc": "Monday", "ccccc": "",
+			"q": "", "qq": "",
+			"W": "", "WW": "", "kk": "", "kkkk": "", "n": "", "nn": "", "ii": "", "iiii": "",
+			"a": "PM",
+			"H": "", "HH": "15",
+			"h": "3", "hh": "03",
+			"m": "4", "mm": "04",
+			"s": "5", "ss": "05",
+			"S": "", "SSS": ".000",
+			"u": ".000", "uu": "", "uuu": "",
+			"Z": "", "ZZ": "-07:00", "ZZZ": "-0700", "ZZZZ": "MST", "ZZZZZ": "",
+			"z": "",
+			"D": "", "DD": "", "DDD": "", "DDDD": "",
+			"t": "", "tt": "", "ttt": "", "tttt": "",
+			"T": "", "TT": "", "TTT": "", "TTTT": "",
+			"f": "", "ff": "", "fff": "", "ffff": "",
+			"F": "", "FF": "", "FFF": "", "FFFF": "",
+			"X": "", "x": "",
+		},
+		literal: singleQuoted,
+		strict:  true,
+	}
+)
+
+// withFractions adds the tokens for 1 to 9 digits of fractional seconds, e.g. "SSS" for ".000".
+func withFractions(tokens map[string]string, letter, digit string) map[string]string {
+	for i := 1; i <= 9; i++ {
+		tokens[strings.Repeat(letter, i)] = digit[:1] + strings.Repeat(digit[1:], i)
+	}
+	return tokens
+}
+
+// singleQuoted handles 'quoted text' and a doubled single quote for a single quote.
+func singleQuoted(pattern string, i int) (string, int, bool, error) {
+	if pattern[i] != '\'' {
+		return "", i, false, nil
+	}
+	if strings.HasPrefix(pattern[i:], "''") {
+		return "'", i + 2, true, nil
+	}
+
+	var sb strings.Builder
+	for j := i + 1; j < len(pattern); j++ {
+		if pattern[j] != '\'' {
+			sb.WriteByte(pattern[j])
+			continue
+		}
+		if strings.HasPrefix(pattern[j:], "''") {
+			sb.WriteByte('\'')
+			j++
+			continue
+		}
+		return sb.String(), j + 1, true, nil
+	}
+	return "", i, false, fmt.Errorf("unterminated quote at position %v", i)
+}
+
+// dotNetLiteral handles 'quoted' and "quoted" text, \ escapes and % in front of single letter tokens.
+func dotNetLiteral(pattern string, i int) (string, int, bool, error) {
+	switch pattern[i] {
+	case '\\':
+		if i+1 >= len(pattern) {
+			return "", i, false, fmt.Errorf("incomplete escape at position %v", i)
+		}
+		return pattern[i+1 : i+2], i + 2, true, nil
+	case '%':
+		// "%d" is the single token "d"
+		return "", i + 1, true, nil
+	case '\'', '"':
+		end := strings.IndexByte(pattern[i+1:], pattern[i])
+		if end < 0 {
+			return "", i, false, fmt.Errorf("unterminated quote at position %v", i)
+		}
+		return pattern[i+1 : i+1+end], i + 2 + end, true, nil
+	}
+	return "", i, false, nil
+}
+
+// bracketed handles [escaped text].
+func bracketed(pattern string, i int) (string, int, bool, error) {
+	if pattern[i] != '[' {
+		return "", i, false, nil
+	}
+	end := strings.IndexByte(pattern[i:], ']')
+	if end < 0 {
+		return "", i, false, fmt.Errorf("unterminated '[' at position %v", i)
+	}
+	return pattern[i+1 : i+end], i + end + 1, true, nil
+}
+
+// layoutProbe is a time with different components than Go's reference time,
+// formatting text with this time changes it when it contains layout elements.
+var layoutProbe = time.Date(1999, time.December, 31, 10, 58, 59, 987654321, time.FixedZone("QQQ", -(3*3600+30*60)))
+
+// layoutPart is either a Go layout element or literal text.
+type layoutPart struct {
+	text     string
+	element  bool
+	position int
+}
+
+// translate converts the pattern to a Go layout.
+func (d layoutDialect) translate(pattern string) (string, error) {
+	var parts []layoutPart
+
+	addLiteral := func(text string, position int) {
+		if text == "" {
+			return
+		}
+		if n := len(parts); n > 0 && !parts[n-1].element {
+			parts[n-1].text += text
+			return
+		}
+		parts = append(parts, layoutPart{text: text, position: position})
+	}
+
+	for i := 0; i < len(pattern); {
+		text, next, ok, err := d.literal(pattern, i)
+		if err != nil {
+			return "", fmt.Errorf("%v pattern '%v': %w", d.name, pattern, err)
+		}
+		if ok {
+			addLiteral(text, i)
+			i = next
+			continue
+		}
+
+		if strings.IndexByte(d.reserved, pattern[i]) >= 0 {
+			return "", fmt.Errorf("%w: %v pattern character '%c' at position %v", ErrUnsupportedToken, d.name, pattern[i], i)
+		}
+
+		r := rune(pattern[i])
+		if !unicode.IsLetter(r) || r > unicode.MaxASCII {
+			addLiteral(pattern[i:i+1], i)
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(pattern) && pattern[end] == pattern[i] {
+			end++
+		}
+		token := pattern[i:end]
+		if d.ordinal != "" && strings.HasPrefix(pattern[end:], d.ordinal) {
+			if _, exists := d.tokens[token+d.ordinal]; exists {
+				token += d.ordinal
+				end += len(d.ordinal)
+			}
+		}
+
+		element, known := d.tokens[token]
+		switch {
+		case !known && !d.strict:
+			addLiteral(token, i)
+			i = end
+			continue
+		case !known:
+			return "", fmt.Errorf("%w: unknown %v token '%v' at position %v", ErrUnsupportedToken, d.name, token, i)
+		case element == "":
+			return "", fmt.Errorf("%w: %v token '%v' at position %v", ErrUnsupportedToken, d.name, token, i)
+		}
+
+		// fractional seconds include the separator in Go layouts
+		if strings.HasPrefix(element, ".") {
+			n := len(parts)
+			if n == 0 || parts[n-1].element || !strings.ContainsAny(parts[n-1].text[len(parts[n-1].text)-1:], ".,") {
+				return "", fmt.Errorf("%w: %v token '%v' at position %v has to follow '.' or ','", ErrUnsupportedToken, d.name, token, i)
+			}
+			last := &parts[n-1]
+			element = last.text[len(last.text)-1:] + element[1:]
+			last.text = last.text[:len(last.text)-1]
+			if last.text == "" {
+				parts = parts[:n-1]
+			}
+		}
+
+		parts = append(parts, layoutPart{text: element, element: true, position: i})
+		i = end
+	}
+
+	return joinLayout(parts, fmt.Sprintf("%v pattern '%v'", d.name, pattern))
+}
+
+// joinLayout joins the parts to a Go layout and verifies that Go doesn't interpret the literal text as layout elements.
+func joinLayout(parts []layoutPart, description string) (string, error) {
+	var layout, expected strings.Builder
+	for _, part := range parts {
+		layout.WriteString(part.text)
+		if part.element {
+			expected.WriteString(layoutProbe.Format(part.text))
+			continue
+		}
+		if layoutProbe.Format(part.text) != part.text {
+			return "", fmt.Errorf("%w: literal text '%v' at position %v contains Go layout elements", ErrUnsupportedToken, part.text, part.position)
+		}
+		expected.WriteString(part.text)
+	}
+
+	// elements and literals might merge, e.g. "01" followed by the literal "1"
+	if layoutProbe.Format(layout.String()) != expected.String() {
+		return "", fmt.Errorf("%w: literal text of %v merges with the surrounding elements", ErrUnsupportedToken, description)
+	}
+
+	return layout.String(), nil
+}
+
+// JavaLayout converts a Java DateTimeFormatter (or SimpleDateFormat) pattern, e.g. "yyyy-MM-dd'T'HH:mm:ss.SSSXXX", to a Go layout.
+// Text in single quotes is literal. Tokens without a Go equivalent, such as "H" (hour without padding), "w" (week) or "[" (optional sections),
+// return ErrUnsupportedToken.
+func JavaLayout(pattern string) (string, error) {
+	return javaDialect.translate(pattern)
+}
+
+// DotNetLayout converts a .NET custom date and time format, e.g. "yyyy-MM-ddTHH:mm:ss.fffK", to a Go layout.
+// Text in single or double quotes and characters escaped with "\" are literal, "%" marks single letter tokens.
+// Tokens without a Go equivalent, such as "H" (hour without padding) or "t" (A/P), return ErrUnsupportedToken.
+func DotNetLayout(pattern string) (string, error) {
+	return dotNetDialect.translate(pattern)
+}
+
+// MomentLayout converts a moment.js format, e.g. "YYYY-MM-DD HH:mm", to a Go layout.
+// Text in square brackets is literal. Tokens without a Go equivalent, such as "Do" (ordinal day),
+// "Q" (quarter) or "X" (Unix timestamp), return ErrUnsupportedToken.
+func MomentLayout(pattern string) (string, error) {
+	return momentDialect.translate(pattern)
+}
+
+// LuxonLayout converts a Luxon format, e.g. "yyyy-MM-dd'T'HH:mm:ss.SSSZZ", to a Go layout.
+// Text in single quotes is literal. Tokens without a Go equivalent, such as localized macros ("D", "t") or
+// "o" (ordinal day), return ErrUnsupportedToken.
+func LuxonLayout(pattern string) (string, error) {
+	return luxonDialect.translate(pattern)
+}
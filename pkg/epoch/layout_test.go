@@ -0,0 +1,73 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLayouts(t *testing.T) {
+	testCases := []struct {
+		description string
+		translate   func(string) (string, error)
+		pattern     string
+		expected    string
+		expectedErr error
+	}{
+		{description: "java/iso", translate: JavaLayout, pattern: "yyyy-MM-dd'T'HH:mm:ss.SSSXXX", expected: "2006-01-02T15:04:05.000Z07:00"},
+		{description: "java/names", translate: JavaLayout, pattern: "EEEE, d MMMM yyyy h:mm a z", expected: "Monday, 2 January 2006 3:04 PM MST"},
+		{description: "java/escaped quote", translate: JavaLayout, pattern: "HH 'o''clock'", expected: "15 o'clock"},
+		{description: "java/comma fraction", translate: JavaLayout, pattern: "ss,SSSSSS", expected: "05,000000"},
+		{description: "java/unpadded hour", translate: JavaLayout, pattern: "H:mm", expectedErr: errors.New("token not supported by Go layouts: Java token 'H' at position 0")},
+		{description: "java/unknown letter", translate: JavaLayout, pattern: "yyyy-MM-ddTHH", expectedErr: errors.New("token not supported by Go layouts: unknown Java token 'T' at position 10")},
+		{description: "java/optional section", translate: JavaLayout, pattern: "HH:mm[:ss]", expectedErr: errors.New("token not supported by Go layouts: Java pattern character '[' at position 5")},
+		{description: "java/fraction without dot", translate: JavaLayout, pattern: "ssSSS", expectedErr: errors.New("token not supported by Go layouts: Java token 'SSS' at position 2 has to follow '.' or ','")},
+		{description: "java/unterminated quote", translate: JavaLayout, pattern: "HH 'h", expectedErr: errors.New("Java pattern 'HH 'h': unterminated quote at position 3")},
+		{description: "java/go element in literal", translate: JavaLayout, pattern: "'Jan' MM", expectedErr: errors.New("token not supported by Go layouts: literal text 'Jan ' at position 0 contains Go layout elements")},
+
+		{description: "dotnet/iso", translate: DotNetLayout, pattern: "yyyy-MM-ddTHH:mm:ss.fffK", expected: "2006-01-02T15:04:05.000Z07:00"},
+		{description: "dotnet/optional fraction", translate: DotNetLayout, pattern: "HH:mm:ss.FFFFFFF zzz", expected: "15:04:05.9999999 -07:00"},
+		{description: "dotnet/quotes and escapes", translate: DotNetLayout, pattern: `dddd, dd MMMM yyyy 'at' hh:mm tt \h`, expected: "Monday, 02 January 2006 at 03:04 PM h"},
+		{description: "dotnet/single letter", translate: DotNetLayout, pattern: "%d", expected: "2"},
+		{description: "dotnet/designator", translate: DotNetLayout, pattern: "h t", expectedErr: errors.New("token not supported by Go layouts: .NET token 't' at position 2")},
+
+		{description: "moment/date time", translate: MomentLayout, pattern: "YYYY-MM-DD HH:mm", expected: "2006-01-02 15:04"},
+		{description: "moment/escaped", translate: MomentLayout, pattern: "ddd, MMM D YYYY [at] h:mm a Z", expected: "Mon, Jan 2 2006 at 3:04 pm -07:00"},
+		{description: "moment/fraction", translate: MomentLayout, pattern: "HH:mm:ss.SSS ZZ", expected: "15:04:05.000 -0700"},
+		{description: "moment/ordinal", translate: MomentLayout, pattern: "MMMM Do", expectedErr: errors.New("token not supported by Go layouts: moment.js token 'Do' at position 5")},
+		{description: "moment/merging literal", translate: MomentLayout, pattern: "[_]D", expectedErr: errors.New("token not supported by Go layouts: literal text of moment.js pattern '[_]D' merges with the surrounding elements")},
+		{description: "moment/unterminated", translate: MomentLayout, pattern: "[at", expectedErr: errors.New("moment.js pattern '[at': unterminated '[' at position 0")},
+
+		{description: "luxon/iso", translate: LuxonLayout, pattern: "yyyy-MM-dd'T'HH:mm:ss.SSSZZ", expected: "2006-01-02T15:04:05.000-07:00"},
+		{description: "luxon/names", translate: LuxonLayout, pattern: "cccc, LLLL d, h:mm a ZZZZ", expected: "Monday, January 2, 3:04 PM MST"},
+		{description: "luxon/macro", translate: LuxonLayout, pattern: "DD t", expectedErr: errors.New("token not supported by Go layouts: Luxon token 'DD' at position 0")},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := tt.translate(tt.pattern)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
+
+func TestLayoutsRoundTrip(t *testing.T) {
+	given := time.Date(2024, 3, 9, 17, 4, 5, 6000000, time.FixedZone("", 3600))
+
+	layout, err := JavaLayout("yyyy-MM-dd'T'HH:mm:ss.SSSXXX")
+	equal(t, err, nil)
+
+	formatted := given.Format(layout)
+	equal(t, formatted, "2024-03-09T17:04:05.006+01:00")
+
+	parsed, err := time.Parse(layout, formatted)
+	equal(t, err, nil)
+	equal(t, parsed.Equal(given), true)
+}
@@ -0,0 +1,343 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrUnknownLocale is returned when a locale is not supported.
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// Locale contains the names of months and weekdays and the common date orders of a language.
+type Locale struct {
+	Name          string
+	Months        [12]string // January first
+	ShortMonths   [12]string
+	Weekdays      [7]string // Sunday first
+	ShortWeekdays [7]string
+	AM, PM        string
+	// Layouts are the common Go layouts of the locale (with English names, e.g. "2. January 2006"),
+	// tried by the Parser before the built-in layouts.
+	Layouts []string
+}
+
+var english = Locale{
+	Name:          "en",
+	Months:        [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	ShortMonths:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	Weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	ShortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	AM:            "AM",
+	PM:            "PM",
+}
+
+// locales are the built-in locales by their language code.
+var locales = map[string]Locale{
+	"en": english,
+	"de": {
+		Name:          "de",
+		Months:        [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths:   [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortWeekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		AM:            "AM",
+		PM:            "PM",
+		Layouts: []string{
+			"2.1.2006 15:04:05", "2.1.2006 15:04", "2.1.2006",
+			"Monday, 2. January 2006 15:04", "Monday, 2. January 2006", "2. January 2006 15:04", "2. January 2006",
+		},
+	},
+	"fr": {
+		Name:          "fr",
+		Months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortWeekdays: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		AM:            "AM",
+		PM:            "PM",
+		Layouts: []string{
+			"2/1/2006 15:04:05", "2/1/2006 15:04", "2/1/2006",
+			"Monday 2 January 2006 15:04", "Monday 2 January 2006", "2 January 2006 15:04", "2 January 2006",
+		},
+	},
+	"es": {
+		Name:          "es",
+		Months:        [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortMonths:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		Weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		ShortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		AM:            "a. m.",
+		PM:            "p. m.",
+		Layouts: []string{
+			"2/1/2006 15:04:05", "2/1/2006 15:04", "2/1/2006",
+			"Monday, 2 de January de 2006", "2 de January de 2006 15:04", "2 de January de 2006",
+		},
+	},
+	"it": {
+		Name:          "it",
+		Months:        [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		ShortMonths:   [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		Weekdays:      [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		ShortWeekdays: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		AM:            "AM",
+		PM:            "PM",
+		Layouts: []string{
+			"2/1/2006 15:04:05", "2/1/2006 15:04", "2/1/2006",
+			"Monday 2 January 2006 15:04", "Monday 2 January 2006", "2 January 2006 15:04", "2 January 2006",
+		},
+	},
+	"nl": {
+		Name:          "nl",
+		Months:        [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		ShortMonths:   [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Weekdays:      [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		ShortWeekdays: [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		AM:            "a.m.",
+		PM:            "p.m.",
+		Layouts: []string{
+			"2-1-2006 15:04:05", "2-1-2006 15:04", "2-1-2006",
+			"Monday 2 January 2006 15:04", "Monday 2 January 2006", "2 January 2006 15:04", "2 January 2006",
+		},
+	},
+	"pt": {
+		Name:          "pt",
+		Months:        [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		ShortMonths:   [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		Weekdays:      [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		ShortWeekdays: [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		AM:            "AM",
+		PM:            "PM",
+		Layouts: []string{
+			"2/1/2006 15:04:05", "2/1/2006 15:04", "2/1/2006",
+			"Monday, 2 de January de 2006", "2 de January de 2006 15:04", "2 de January de 2006",
+		},
+	},
+	"ja": {
+		Name:          "ja",
+		Months:        [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		ShortMonths:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		Weekdays:      [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		ShortWeekdays: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		AM:            "午前",
+		PM:            "午後",
+		Layouts: []string{
+			"2006年1月2日 15:04:05", "2006年1月2日 15:04", "2006年1月2日(Mon) 15:04", "2006年1月2日(Mon)", "2006年1月2日",
+			"2006/1/2 15:04:05", "2006/1/2 15:04", "2006/1/2",
+		},
+	},
+}
+
+// LookupLocale returns the built-in locale for a language code, e.g. "de".
+// Regions and encodings are ignored, e.g. "de_AT.UTF-8" is "de". "C" and "POSIX" are English.
+func LookupLocale(name string) (Locale, error) {
+	language := strings.ToLower(name)
+	if i := strings.IndexAny(language, "_-.@"); i >= 0 {
+		language = language[:i]
+	}
+	if language == "c" || language == "posix" {
+		language = "en"
+	}
+
+	locale, ok := locales[language]
+	if !ok {
+		return Locale{}, fmt.Errorf("%w: '%v' (supported: %v)", ErrUnknownLocale, name, strings.Join(Locales(), ", "))
+	}
+	return locale, nil
+}
+
+// Locales returns the language codes of the built-in locales.
+func Locales() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Format returns the time formatted with the Go layout (e.g. "Monday 2 January 2006")
+// using the names of the locale.
+func (l Locale) Format(t time.Time, layout string) string {
+	var sb strings.Builder
+	for _, part := range splitGoLayout(layout) {
+		switch {
+		case !part.element:
+			sb.WriteString(part.text)
+		case part.text == "January":
+			sb.WriteString(l.Months[t.Month()-1])
+		case part.text == "Jan":
+			sb.WriteString(l.ShortMonths[t.Month()-1])
+		case part.text == "Monday":
+			sb.WriteString(l.Weekdays[t.Weekday()])
+		case part.text == "Mon":
+			sb.WriteString(l.ShortWeekdays[t.Weekday()])
+		case part.text == "PM":
+			sb.WriteString(l.meridiem(t))
+		case part.text == "pm":
+			sb.WriteString(strings.ToLower(l.meridiem(t)))
+		default:
+			sb.WriteString(t.Format(part.text))
+		}
+	}
+	return sb.String()
+}
+
+// Parse parses the value with the Go layout (e.g. "2. January 2006") using the names of the locale.
+// Names are matched case-insensitively in their full or abbreviated form, whitespace in the layout
+// matches any amount of whitespace. The location is used for values without a time zone.
+func (l Locale) Parse(layout, value string, loc *time.Location) (time.Time, error) {
+	var tokens []strftimeToken
+	for _, part := range splitGoLayout(layout) {
+		if !part.element {
+			tokens = append(tokens, strftimeToken{literal: part.text})
+			continue
+		}
+		tokens = append(tokens, goParseToken(part.text))
+	}
+	return l.parse(value, layout, tokens, loc)
+}
+
+// Strftime formats t according to the strftime format (see Strftime) using the names of the locale.
+func (l Locale) Strftime(t time.Time, format string) (string, error) {
+	tokens, err := tokenizeStrftime(format)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, token := range tokens {
+		if token.verb == 0 {
+			sb.WriteString(token.literal)
+			continue
+		}
+		sb.WriteString(l.formatStrftimeToken(t, token))
+	}
+	return sb.String(), nil
+}
+
+// Strptime parses the value according to the strftime format (see Strptime) using the names of the locale.
+func (l Locale) Strptime(value, format string, loc *time.Location) (time.Time, error) {
+	tokens, err := tokenizeStrftime(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.parse(value, format, tokens, loc)
+}
+
+func (l Locale) meridiem(t time.Time) string {
+	if t.Hour() >= 12 {
+		return l.PM
+	}
+	return l.AM
+}
+
+// goLayoutElements are the elements of Go layouts, longer elements first.
+var goLayoutElements = []string{
+	"January", "Monday",
+	"-07:00:00", "Z07:00:00", "-070000", "Z070000", "-07:00", "Z07:00", "-0700", "Z0700",
+	"2006", "Jan", "Mon", "MST", "-07", "Z07", "002", "__2",
+	"01", "02", "03", "04", "05", "06", "15", "_2", "PM", "pm",
+	"1", "2", "3", "4", "5",
+}
+
+// splitGoLayout splits the Go layout into literal text and layout elements like time.Format does.
+func splitGoLayout(layout string) []layoutPart {
+	var (
+		parts        []layoutPart
+		literalStart = 0
+	)
+
+	addElement := func(i, end int) {
+		if literalStart < i {
+			parts = append(parts, layoutPart{text: layout[literalStart:i], position: literalStart})
+		}
+		parts = append(parts, layoutPart{text: layout[i:end], element: true, position: i})
+		literalStart = end
+	}
+
+	for i := 0; i < len(layout); {
+		// "_2006" is a literal underscore followed by the year
+		if strings.HasPrefix(layout[i:], "_2006") {
+			i++
+			continue
+		}
+
+		// fractional seconds, e.g. ".000" or ",999", not followed by other digits
+		if (layout[i] == '.' || layout[i] == ',') && i+1 < len(layout) && (layout[i+1] == '0' || layout[i+1] == '9') {
+			end := i + 1
+			for end < len(layout) && layout[end] == layout[i+1] {
+				end++
+			}
+			if end == len(layout) || layout[end] < '0' || layout[end] > '9' {
+				addElement(i, end)
+				i = end
+				continue
+			}
+		}
+
+		matched := false
+		for _, element := range goLayoutElements {
+			if strings.HasPrefix(layout[i:], element) {
+				addElement(i, i+len(element))
+				i += len(element)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			i++
+		}
+	}
+
+	if literalStart < len(layout) {
+		parts = append(parts, layoutPart{text: layout[literalStart:], position: literalStart})
+	}
+	return parts
+}
+
+// goParseTokens are the strftime conversions used to parse Go layout elements.
+var goParseTokens = map[string]strftimeToken{
+	"2006":    {verb: 'Y'},
+	"06":      {verb: 'y'},
+	"January": {verb: 'B'},
+	"Jan":     {verb: 'b'},
+	"01":      {verb: 'm'},
+	"1":       {verb: 'm', flag: '-'},
+	"Monday":  {verb: 'A'},
+	"Mon":     {verb: 'a'},
+	"02":      {verb: 'd'},
+	"2":       {verb: 'd', flag: '-'},
+	"_2":      {verb: 'e'},
+	"002":     {verb: 'j'},
+	"__2":     {verb: 'j', flag: '_'},
+	"15":      {verb: 'H'},
+	"03":      {verb: 'I'},
+	"3":       {verb: 'I', flag: '-'},
+	"04":      {verb: 'M'},
+	"4":       {verb: 'M', flag: '-'},
+	"05":      {verb: 'S'},
+	"5":       {verb: 'S', flag: '-'},
+	"PM":      {verb: 'p'},
+	"pm":      {verb: 'P'},
+	"MST":     {verb: 'Z'},
+}
+
+// goParseToken returns the strftime conversion for parsing a Go layout element.
+// Fractional seconds keep their separator, fractions with nines are optional like in time.Parse.
+func goParseToken(element string) strftimeToken {
+	if token, ok := goParseTokens[element]; ok {
+		return token
+	}
+	switch element[0] {
+	case '.', ',':
+		if element[1] == '9' {
+			return strftimeToken{verb: 'N', literal: element[:1], optional: true}
+		}
+		return strftimeToken{verb: 'N', literal: element[:1], width: len(element) - 1}
+	}
+	// all remaining elements are offsets
+	return strftimeToken{verb: 'z'}
+}
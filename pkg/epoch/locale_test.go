@@ -0,0 +1,165 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLookupLocale(t *testing.T) {
+	testCases := []struct {
+		description string
+		name        string
+		expected    string
+		expectedErr error
+	}{
+		{description: "language", name: "de", expected: "de"},
+		{description: "region and encoding", name: "fr_CA.UTF-8", expected: "fr"},
+		{description: "bcp 47", name: "pt-BR", expected: "pt"},
+		{description: "c locale", name: "C", expected: "en"},
+		{description: "unknown", name: "xx", expectedErr: errors.New("unknown locale: 'xx' (supported: de, en, es, fr, it, ja, nl, pt)")},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := LookupLocale(tt.name)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got.Name, tt.expected)
+		})
+	}
+}
+
+func TestLocaleFormat(t *testing.T) {
+	given := time.Date(2020, 3, 3, 14, 5, 6, 123000000, time.UTC)
+
+	testCases := []struct {
+		locale   string
+		layout   string
+		expected string
+	}{
+		{locale: "de", layout: "Monday, 2. January 2006 15:04", expected: "Dienstag, 3. März 2020 14:05"},
+		{locale: "de", layout: "Mon, 02. Jan 06", expected: "Di, 03. Mär 20"},
+		{locale: "fr", layout: "Monday 2 January 2006", expected: "mardi 3 mars 2020"},
+		{locale: "fr", layout: "Mon 2 Jan", expected: "mar. 3 mars"},
+		{locale: "es", layout: "Monday, 2 de January de 2006 3:04 PM", expected: "martes, 3 de marzo de 2020 2:05 p. m."},
+		{locale: "it", layout: "Monday 2 January 2006", expected: "martedì 3 marzo 2020"},
+		{locale: "nl", layout: "Mon 2 Jan 2006", expected: "di 3 mrt 2020"},
+		{locale: "pt", layout: "Monday, 2 de January de 2006", expected: "terça-feira, 3 de março de 2020"},
+		{locale: "ja", layout: "2006年January2日(Mon) PM3:04", expected: "2020年3月3日(火) 午後2:05"},
+		{locale: "en", layout: time.RFC1123, expected: "Tue, 03 Mar 2020 14:05:06 UTC"},
+		{locale: "en", layout: "_2006 __2 _2 .000 ,999 Z07:00 -0700 MST pm", expected: "_2020  63  3 .123 ,123 Z +0000 UTC pm"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.locale+" "+tt.layout, func(t *testing.T) {
+			locale, err := LookupLocale(tt.locale)
+			equal(t, err, nil)
+			got := locale.Format(given, tt.layout)
+			equal(t, got, tt.expected)
+			if tt.locale == "en" {
+				equal(t, got, given.Format(tt.layout))
+			}
+		})
+	}
+}
+
+func TestLocaleParse(t *testing.T) {
+	testCases := []struct {
+		locale      string
+		layout      string
+		value       string
+		expected    time.Time
+		expectedErr error
+	}{
+		{locale: "de", layout: "2. January 2006", value: "25. Januar 2019", expected: time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC)},
+		{locale: "de", layout: "2. January 2006", value: "25. Jan 2019", expected: time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC)},
+		{locale: "de", layout: "2. Jan 2006", value: "3. MÄRZ 2020", expected: time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{locale: "fr", layout: "Monday 2 January 2006", value: "mardi 3 mars 2020", expected: time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{locale: "fr", layout: "2 Jan 2006", value: "14 juil. 2021", expected: time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC)},
+		{locale: "fr", layout: "2 January 2006", value: "14 juillet 2021", expected: time.Date(2021, 7, 14, 0, 0, 0, 0, time.UTC)},
+		{locale: "es", layout: "2 de January de 2006 3:04 PM", value: "3 de septiembre de 2020 2:05 p. m.", expected: time.Date(2020, 9, 3, 14, 5, 0, 0, time.UTC)},
+		{locale: "pt", layout: "Monday, 2 de January de 2006", value: "segunda-feira, 2 de março de 2020", expected: time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{locale: "ja", layout: "2006年1月2日(Mon)", value: "2019年11月25日(月)", expected: time.Date(2019, 11, 25, 0, 0, 0, 0, time.UTC)},
+		{locale: "en", layout: "2006-01-02 15:04:05.999 -07:00", value: "2024-01-05 07:06:05 +01:00", expected: time.Date(2024, 1, 5, 7, 6, 5, 0, time.FixedZone("", 3600))},
+		{locale: "en", layout: "2006-01-02 15:04:05,000", value: "2024-01-05 07:06:05,120", expected: time.Date(2024, 1, 5, 7, 6, 5, 120000000, time.UTC)},
+		{
+			locale:      "de",
+			layout:      "2. January 2006",
+			value:       "25. Janvier 2019",
+			expectedErr: errors.New("failed to convert string to time: '25. Janvier 2019' doesn't match '2. January 2006': expected a number"),
+		},
+		{
+			locale:      "en",
+			layout:      "15:04:05.000",
+			value:       "07:06:05",
+			expectedErr: errors.New("failed to convert string to time: '07:06:05' doesn't match '15:04:05.000': expected '.'"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.locale+" "+tt.value, func(t *testing.T) {
+			locale, err := LookupLocale(tt.locale)
+			equal(t, err, nil)
+			got, err := locale.Parse(tt.layout, tt.value, time.UTC)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
+
+func TestLocaleStrftime(t *testing.T) {
+	de, err := LookupLocale("de")
+	equal(t, err, nil)
+
+	got, err := de.Strftime(time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC), "%A, %-d. %B %Y (%a, %b)")
+	equal(t, err, nil)
+	equal(t, got, "Freitag, 25. Januar 2019 (Fr, Jan)")
+
+	parsed, err := de.Strptime("Freitag, 25. Januar 2019", "%A, %-d. %B %Y", time.UTC)
+	equal(t, err, nil)
+	equal(t, parsed, time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC))
+}
+
+func TestParserLocale(t *testing.T) {
+	de, err := LookupLocale("de")
+	equal(t, err, nil)
+
+	custom, err := ParseLayout("{dddd}, {D}. {MMMM} {YYYY}")
+	equal(t, err, nil)
+
+	parser := Parser{Layouts: []Layout{custom}, Locale: &de}
+
+	testCases := []struct {
+		input        string
+		expected     time.Time
+		expectedName string
+	}{
+		{input: "Freitag, 25. Januar 2019", expected: time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC), expectedName: "{dddd}, {D}. {MMMM} {YYYY}"},
+		{input: "25.01.2019 14:30", expected: time.Date(2019, 1, 25, 14, 30, 0, 0, time.UTC), expectedName: "2.1.2006 15:04"},
+		{input: "3. März 2020", expected: time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC), expectedName: "2. January 2006"},
+		{input: "2019-01-25T14:30:00Z", expected: time.Date(2019, 1, 25, 14, 30, 0, 0, time.UTC), expectedName: time.RFC3339},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.input, func(t *testing.T) {
+			got, name, err := parser.Parse(tt.input, time.UTC)
+			equal(t, err, nil)
+			equal(t, got, tt.expected)
+			equal(t, name, tt.expectedName)
+		})
+	}
+
+	equal(t, custom.WithLocale(de).Format(time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC)), "Freitag, 25. Januar 2019")
+}
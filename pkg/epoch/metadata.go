@@ -0,0 +1,221 @@
+package epoch
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrMetadata is returned when the embedded times of an archive or image can't be read.
+var ErrMetadata = errors.New("failed to read metadata")
+
+// errUnknownMetadata is returned for data in other formats.
+var errUnknownMetadata = fmt.Errorf("%w: unknown format, expected a tar or zip archive or a JPEG or TIFF image", ErrMetadata)
+
+// metadataHeaderSize is the size of the beginning of the data needed to detect the format, a tar header.
+const metadataHeaderSize = 512
+
+// ReadMetadata detects whether the data is a tar archive (optionally gzip compressed), a zip archive
+// or a JPEG or TIFF image and returns the embedded times (see ReadTar, ReadZip and ReadEXIF).
+// Times without timezone are interpreted in the location. Only the needed parts of the data are read,
+// tar archives are streamed.
+func ReadMetadata(r io.ReaderAt, size int64, loc *time.Location) ([]Field, error) {
+	header := make([]byte, min(size, metadataHeaderSize))
+	if _, err := r.ReadAt(header, 0); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%w: %v", ErrMetadata, err)
+	}
+
+	switch {
+	case isZip(header):
+		return ReadZip(r, size, loc)
+	case isGzip(header), isTar(header):
+		return readTar(io.NewSectionReader(r, 0, size), header)
+	case isEXIF(header):
+		return ReadEXIF(r, size, loc)
+	}
+	return nil, errUnknownMetadata
+}
+
+// ReadMetadataStream is ReadMetadata for inputs without random access, e.g. stdin.
+// Tar archives are streamed, zip archives and images are read into memory.
+func ReadMetadataStream(r io.Reader, loc *time.Location) ([]Field, error) {
+	buffered := bufio.NewReaderSize(r, metadataHeaderSize)
+	header, err := buffered.Peek(metadataHeaderSize)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%w: %v", ErrMetadata, err)
+	}
+
+	switch {
+	case isGzip(header), isTar(header):
+		return readTar(buffered, header)
+	case isZip(header), isEXIF(header):
+		data, err := io.ReadAll(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMetadata, err)
+		}
+		return ReadMetadata(bytes.NewReader(data), int64(len(data)), loc)
+	}
+	return nil, errUnknownMetadata
+}
+
+// readTar reads a tar archive, which is decompressed when the header has the gzip magic.
+func readTar(r io.Reader, header []byte) ([]Field, error) {
+	if !isGzip(header) {
+		return ReadTar(r)
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMetadata, err)
+	}
+	return ReadTar(gz)
+}
+
+func isZip(header []byte) bool {
+	return bytes.HasPrefix(header, []byte("PK\x03\x04")) || bytes.HasPrefix(header, []byte("PK\x05\x06"))
+}
+
+func isGzip(header []byte) bool {
+	return bytes.HasPrefix(header, []byte{0x1f, 0x8b})
+}
+
+// isTar reports whether the header has the magic of USTAR, PAX or GNU tar headers.
+func isTar(header []byte) bool {
+	return len(header) >= 262 && bytes.Equal(header[257:262], []byte("ustar"))
+}
+
+// isEXIF reports whether the header is the beginning of a JPEG or TIFF image.
+func isEXIF(header []byte) bool {
+	return bytes.HasPrefix(header, []byte{0xff, 0xd8}) || bytes.HasPrefix(header, []byte("II*\x00")) || bytes.HasPrefix(header, []byte("MM\x00*"))
+}
+
+// ReadTar returns the modification times of the tar entries, named after the entry, e.g. "bin/epoch mtime".
+// PAX headers have nanosecond precision and can contain access and change times, other formats have seconds.
+func ReadTar(r io.Reader) ([]Field, error) {
+	var fields []Field
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMetadata, err)
+		}
+
+		fields = append(fields, Field{Name: header.Name + " mtime", Time: header.ModTime})
+		if !header.AccessTime.IsZero() {
+			fields = append(fields, Field{Name: header.Name + " atime", Time: header.AccessTime})
+		}
+		if !header.ChangeTime.IsZero() {
+			fields = append(fields, Field{Name: header.Name + " ctime", Time: header.ChangeTime})
+		}
+	}
+
+	// the tar package returns EOF for empty inputs as well
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no tar entries found", ErrMetadata)
+	}
+	return fields, nil
+}
+
+// Extra fields of zip entries with timestamps (APPNOTE.TXT 4.5 and Info-ZIP extra fields).
+const (
+	zipExtraNTFS        = 0x000a
+	zipExtraUnix        = 0x000d
+	zipExtraTimestamp   = 0x5455
+	zipExtraInfoZipUnix = 0x5855
+)
+
+// ReadZip returns the modification times of the zip entries, named after the entry, e.g. "bin/epoch mtime".
+// The NTFS extra field (100ns), the extended timestamp and the Unix extra fields (seconds) are in UTC.
+// Without them, the MS-DOS time has a precision of 2 seconds and no timezone, it's interpreted in the location.
+func ReadZip(r io.ReaderAt, size int64, loc *time.Location) ([]Field, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMetadata, err)
+	}
+
+	var fields []Field
+	for _, file := range reader.File {
+		field := Field{Name: file.Name + " mtime"}
+		if t, ok := zipExtraTime(file.Extra); ok {
+			field.Time = t
+		} else if dos, ok := msDOSTime(file.Modified, loc); ok {
+			field.Time = dos
+			field.Note = "MS-DOS time with 2-second precision and without timezone"
+		} else {
+			field.Note = "no valid modification time"
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// msDOSTime returns the MS-DOS time of the modified time of the zip package in the location.
+// Without extra fields, the zip package returns the MS-DOS time in UTC, otherwise, it's replaced with
+// the times of the extra fields, such as zero NTFS times. A zero MS-DOS date (1979-11-30) is no time either.
+func msDOSTime(modified time.Time, loc *time.Location) (time.Time, bool) {
+	if modified.Location() != time.UTC || modified.Year() < 1980 {
+		return time.Time{}, false
+	}
+	return time.Date(modified.Year(), modified.Month(), modified.Day(),
+		modified.Hour(), modified.Minute(), modified.Second(), 0, loc), true
+}
+
+// zipExtraTime returns the modification time of the NTFS extra field, the extended timestamp
+// or the Unix extra fields, in this order.
+func zipExtraTime(extra []byte) (time.Time, bool) {
+	var extended, unix time.Time
+	for len(extra) >= 4 {
+		id, size := binary.LittleEndian.Uint16(extra), int(binary.LittleEndian.Uint16(extra[2:]))
+		if len(extra) < 4+size {
+			break
+		}
+		data := extra[4 : 4+size]
+		extra = extra[4+size:]
+
+		switch id {
+		case zipExtraNTFS:
+			// reserved (4), then attributes with tag (2) and size (2), tag 1 has mtime, atime and ctime
+			for data = data[min(4, len(data)):]; len(data) >= 4; {
+				tag, attrSize := binary.LittleEndian.Uint16(data), int(binary.LittleEndian.Uint16(data[2:]))
+				if len(data) < 4+attrSize {
+					break
+				}
+				// some writers leave the times zero
+				if tag == 1 && attrSize >= 8 && binary.LittleEndian.Uint64(data[4:]) != 0 {
+					return fileTime(binary.LittleEndian.Uint64(data[4:])), true
+				}
+				data = data[4+attrSize:]
+			}
+		case zipExtraTimestamp:
+			// flags (1), bit 0 indicates the mtime in seconds
+			if len(data) >= 5 && data[0]&1 != 0 {
+				extended = time.Unix(int64(int32(binary.LittleEndian.Uint32(data[1:]))), 0).UTC()
+			}
+		case zipExtraUnix, zipExtraInfoZipUnix:
+			// atime (4), mtime (4)
+			if len(data) >= 8 {
+				unix = time.Unix(int64(int32(binary.LittleEndian.Uint32(data[4:]))), 0).UTC()
+			}
+		}
+	}
+	if !extended.IsZero() {
+		return extended, true
+	}
+	return unix, !unix.IsZero()
+}
+
+// fileTime converts a Windows FILETIME, 100ns intervals since 1601-01-01 UTC.
+// Seconds and remainder are split, as nanoseconds since 1970 only cover the years 1678 to 2262.
+func fileTime(ticks uint64) time.Time {
+	const epochDiff = 11644473600 // 1601-01-01 to 1970-01-01 in seconds
+	return time.Unix(int64(ticks/1e7)-epochDiff, int64(ticks%1e7)*100).UTC()
+}
@@ -0,0 +1,203 @@
+package epoch
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testTar(t *testing.T, headers ...*tar.Header) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	for _, header := range headers {
+		equal(t, writer.WriteHeader(header), nil)
+	}
+	equal(t, writer.Close(), nil)
+	return buf.Bytes()
+}
+
+func testZip(t *testing.T, headers ...*zip.FileHeader) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, header := range headers {
+		_, err := writer.CreateHeader(header)
+		equal(t, err, nil)
+	}
+	equal(t, writer.Close(), nil)
+	return buf.Bytes()
+}
+
+// dosZip returns a zip archive with only the MS-DOS times of the modified times, like archives of legacy tools.
+// The zip package always adds an extended timestamp for the modified time, its ID is replaced with an unknown one.
+func dosZip(t *testing.T, headers ...*zip.FileHeader) []byte {
+	t.Helper()
+	return bytes.ReplaceAll(testZip(t, headers...), []byte{0x55, 0x54, 5, 0, 1}, []byte{0xff, 0xff, 5, 0, 1})
+}
+
+func TestReadMetadata(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	equal(t, err, nil)
+	mtime := time.Date(2024, 2, 29, 12, 30, 15, 123456789, time.UTC)
+
+	pax := testTar(t,
+		&tar.Header{Name: "bin/epoch", Mode: 0o755, ModTime: mtime, AccessTime: mtime.Add(time.Hour), Format: tar.FormatPAX},
+		&tar.Header{Name: "README.md", Mode: 0o644, ModTime: mtime, Format: tar.FormatUSTAR},
+	)
+	var gz bytes.Buffer
+	gzWriter := gzip.NewWriter(&gz)
+	_, err = gzWriter.Write(pax)
+	equal(t, err, nil)
+	equal(t, gzWriter.Close(), nil)
+
+	// NTFS extra field: reserved (4), tag 1 with size 24: mtime, atime, ctime
+	ntfs := binary.LittleEndian.AppendUint16(nil, zipExtraNTFS)
+	ntfs = binary.LittleEndian.AppendUint16(ntfs, 32)
+	ntfs = append(ntfs, 0, 0, 0, 0, 1, 0, 24, 0)
+	for range 3 {
+		ntfs = binary.LittleEndian.AppendUint64(ntfs, uint64(mtime.UnixNano()/100+116444736000000000))
+	}
+
+	// NTFS extra field: reserved (4), tag 1 with size 8: mtime beyond the range of nanoseconds
+	future := time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC)
+	futureNTFS := binary.LittleEndian.AppendUint16(nil, zipExtraNTFS)
+	futureNTFS = binary.LittleEndian.AppendUint16(futureNTFS, 16)
+	futureNTFS = append(futureNTFS, 0, 0, 0, 0, 1, 0, 8, 0)
+	futureNTFS = binary.LittleEndian.AppendUint64(futureNTFS, uint64(future.Unix()+11644473600)*1e7)
+
+	// NTFS extra fields with zero times, the zip package only reads them with all three times
+	zeroNTFS := binary.LittleEndian.AppendUint16(nil, zipExtraNTFS)
+	zeroNTFS = binary.LittleEndian.AppendUint16(zeroNTFS, 16)
+	zeroNTFS = append(zeroNTFS, 0, 0, 0, 0, 1, 0, 8, 0)
+	zeroNTFS = binary.LittleEndian.AppendUint64(zeroNTFS, 0)
+	zeroNTFSTimes := binary.LittleEndian.AppendUint16(nil, zipExtraNTFS)
+	zeroNTFSTimes = binary.LittleEndian.AppendUint16(zeroNTFSTimes, 32)
+	zeroNTFSTimes = append(zeroNTFSTimes, 0, 0, 0, 0, 1, 0, 24, 0)
+	zeroNTFSTimes = append(zeroNTFSTimes, make([]byte, 24)...)
+
+	// Info-ZIP Unix extra field: atime, mtime
+	unix := binary.LittleEndian.AppendUint16(nil, zipExtraInfoZipUnix)
+	unix = binary.LittleEndian.AppendUint16(unix, 8)
+	unix = binary.LittleEndian.AppendUint32(unix, uint32(mtime.Unix()))
+	unix = binary.LittleEndian.AppendUint32(unix, uint32(mtime.Unix()))
+
+	zipped := testZip(t,
+		&zip.FileHeader{Name: "extended", Modified: mtime},
+		&zip.FileHeader{Name: "ntfs", Extra: ntfs},
+		&zip.FileHeader{Name: "unix", Extra: unix},
+		&zip.FileHeader{Name: "future ntfs", Extra: futureNTFS},
+		&zip.FileHeader{Name: "zero ntfs", Extra: append(zeroNTFS, unix...)},
+	)
+
+	// the MS-DOS time is 2024-02-29 12:30:14 with a precision of 2 seconds
+	dosZipped := dosZip(t,
+		&zip.FileHeader{Name: "dos", Modified: mtime},
+		&zip.FileHeader{Name: "zero ntfs dos", Extra: zeroNTFS, Modified: mtime},
+		&zip.FileHeader{Name: "zero ntfs times", Extra: zeroNTFSTimes, Modified: mtime},
+		&zip.FileHeader{Name: "zero dos"},
+	)
+
+	testCases := []struct {
+		description string
+		given       []byte
+		expected    []Field
+		expectedErr error
+	}{
+		{
+			description: "tar",
+			given:       pax,
+			expected: []Field{
+				{Name: "bin/epoch mtime", Time: mtime},
+				{Name: "bin/epoch atime", Time: mtime.Add(time.Hour)},
+				{Name: "README.md mtime", Time: mtime.Truncate(time.Second)},
+			},
+		},
+		{
+			description: "tar.gz",
+			given:       gz.Bytes(),
+			expected: []Field{
+				{Name: "bin/epoch mtime", Time: mtime},
+				{Name: "bin/epoch atime", Time: mtime.Add(time.Hour)},
+				{Name: "README.md mtime", Time: mtime.Truncate(time.Second)},
+			},
+		},
+		{
+			description: "zip",
+			given:       zipped,
+			expected: []Field{
+				{Name: "extended mtime", Time: mtime.Truncate(time.Second)},
+				{Name: "ntfs mtime", Time: mtime.Truncate(100 * time.Nanosecond)},
+				{Name: "unix mtime", Time: mtime.Truncate(time.Second)},
+				{Name: "future ntfs mtime", Time: future},
+				{Name: "zero ntfs mtime", Time: mtime.Truncate(time.Second)},
+			},
+		},
+		{
+			description: "zip with MS-DOS times",
+			given:       dosZipped,
+			expected: []Field{
+				{Name: "dos mtime", Time: time.Date(2024, 2, 29, 12, 30, 14, 0, berlin), Note: "MS-DOS time with 2-second precision and without timezone"},
+				{Name: "zero ntfs dos mtime", Time: time.Date(2024, 2, 29, 12, 30, 14, 0, berlin), Note: "MS-DOS time with 2-second precision and without timezone"},
+				// the zip package replaces the MS-DOS time with the zero NTFS time
+				{Name: "zero ntfs times mtime", Note: "no valid modification time"},
+				{Name: "zero dos mtime", Note: "no valid modification time"},
+			},
+		},
+		{
+			description: "empty zip",
+			given:       testZip(t),
+		},
+		{
+			description: "empty gzip",
+			given:       []byte{0x1f, 0x8b},
+			expectedErr: errors.New("failed to read metadata: unexpected EOF"),
+		},
+		{
+			description: "unknown",
+			given:       []byte("2024-02-29"),
+			expectedErr: errors.New("failed to read metadata: unknown format, expected a tar or zip archive or a JPEG or TIFF image"),
+		},
+	}
+
+	read := map[string]func(data []byte) ([]Field, error){
+		"reader at": func(data []byte) ([]Field, error) {
+			return ReadMetadata(bytes.NewReader(data), int64(len(data)), berlin)
+		},
+		"stream": func(data []byte) ([]Field, error) { return ReadMetadataStream(bytes.NewReader(data), berlin) },
+	}
+
+	for _, tt := range testCases {
+		for name, read := range read {
+			t.Run(tt.description+"/"+name, func(t *testing.T) {
+				got, err := read(tt.given)
+				if err != nil {
+					equalError(t, err, tt.expectedErr)
+					equal(t, errors.Is(err, ErrMetadata), true)
+					return
+				} else if tt.expectedErr != nil {
+					t.Fatalf("expected error %q", tt.expectedErr)
+				}
+
+				for i := range got {
+					if got[i].Time.Location() != berlin {
+						got[i].Time = got[i].Time.UTC()
+					}
+				}
+				equal(t, got, tt.expected)
+			})
+		}
+	}
+}
+
+func TestReadTarEmpty(t *testing.T) {
+	_, err := ReadTar(bytes.NewReader(testTar(t)))
+	equalError(t, err, errors.New("failed to read metadata: no tar entries found"))
+}
@@ -0,0 +1,246 @@
+package epoch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateOrder is the order of the components of numeric dates such as "03/04/2024".
+type DateOrder int
+
+const (
+	// DateOrderAuto accepts every order which results in a valid date, but returns
+	// an *AmbiguousDateError when different orders result in different dates.
+	DateOrderAuto DateOrder = iota
+	// DateOrderMDY is month, day, year (e.g. "03/04/2024" is March 4).
+	DateOrderMDY
+	// DateOrderDMY is day, month, year (e.g. "03/04/2024" is April 3).
+	DateOrderDMY
+	// DateOrderYMD is year, month, day (e.g. "2024/03/04" is March 4).
+	DateOrderYMD
+)
+
+// ParseDateOrder returns the date order for "mdy", "dmy", "ymd" or "auto" (empty).
+func ParseDateOrder(s string) (DateOrder, error) {
+	switch strings.ToLower(s) {
+	case "", "auto":
+		return DateOrderAuto, nil
+	case "mdy":
+		return DateOrderMDY, nil
+	case "dmy":
+		return DateOrderDMY, nil
+	case "ymd":
+		return DateOrderYMD, nil
+	}
+	return DateOrderAuto, fmt.Errorf("unknown date order '%v' (use mdy, dmy or ymd)", s)
+}
+
+func (o DateOrder) String() string {
+	switch o {
+	case DateOrderMDY:
+		return "mdy"
+	case DateOrderDMY:
+		return "dmy"
+	case DateOrderYMD:
+		return "ymd"
+	}
+	return "auto"
+}
+
+// AmbiguousDateError is returned when a numeric date is valid in different orders, e.g. "03/04/2024".
+type AmbiguousDateError struct {
+	Input string
+	// Orders are the matching orders with the resulting Times.
+	Orders []DateOrder
+	Times  []time.Time
+}
+
+func (e *AmbiguousDateError) Error() string {
+	interpretations := make([]string, 0, len(e.Times))
+	for i, t := range e.Times {
+		interpretations = append(interpretations, fmt.Sprintf("%v (%v)", t.Format("2006-01-02"), e.Orders[i]))
+	}
+	return fmt.Sprintf("ambiguous date '%v': %v, specify the date order", e.Input, strings.Join(interpretations, " or "))
+}
+
+// Unwrap returns ErrParseFormatted as the date couldn't be converted.
+func (e *AmbiguousDateError) Unwrap() error {
+	return ErrParseFormatted
+}
+
+// numericTimes are the optional times following numeric dates, seconds might have fractions.
+var numericTimes = []string{"15:04", "15:04:05"}
+
+// ParseNumericDate parses dates with three numeric components separated by "/", "." or "-",
+// e.g. "03/04/2024", "25.01.19" or "2024-01-25", optionally followed by a time such as
+// " 21:51" or "T21:51:38.272". Years with two digits are 1969 to 2068.
+//
+// A year with four digits at the start is always YMD. Otherwise, the preferred order is used when it results
+// in a valid date, else the only valid order. Different valid dates return an *AmbiguousDateError.
+// The returned layout describes the matched order, e.g. "02/01/2006 15:04".
+func ParseNumericDate(input string, order DateOrder, loc *time.Location) (time.Time, string, error) {
+	date, clock, hasClock, fields, ok := splitNumericDate(input)
+	if !ok {
+		return time.Time{}, "", fmt.Errorf("%w: '%v' is no numeric date", ErrParseFormatted, input)
+	}
+	sep := date[len(fields[0])]
+
+	var (
+		clockTime   time.Time
+		clockLayout string
+	)
+	if hasClock {
+		for _, layout := range numericTimes {
+			if t, err := time.Parse(layout, clock); err == nil {
+				clockTime, clockLayout = t, input[len(date):len(date)+1]+layout
+				break
+			}
+		}
+		if clockLayout == "" {
+			return time.Time{}, "", fmt.Errorf("%w: '%v' has no valid time", ErrParseFormatted, input)
+		}
+	}
+
+	var (
+		orders  []DateOrder
+		times   []time.Time
+		layouts []string
+	)
+	for _, candidate := range []DateOrder{DateOrderMDY, DateOrderDMY, DateOrderYMD} {
+		t, layout, ok := numericDate(fields, sep, candidate, loc)
+		if !ok {
+			continue
+		}
+		t = time.Date(t.Year(), t.Month(), t.Day(), clockTime.Hour(), clockTime.Minute(), clockTime.Second(), clockTime.Nanosecond(), loc)
+		if candidate == order {
+			return t, layout + clockLayout, nil
+		}
+		// e.g. "03/03/2024" is the same date in both orders
+		if len(times) > 0 && times[0].Equal(t) {
+			continue
+		}
+		orders, times, layouts = append(orders, candidate), append(times, t), append(layouts, layout+clockLayout)
+	}
+
+	switch len(times) {
+	case 0:
+		return time.Time{}, "", fmt.Errorf("%w: '%v' is no valid date", ErrParseFormatted, input)
+	case 1:
+		return times[0], layouts[0], nil
+	}
+	return time.Time{}, "", &AmbiguousDateError{Input: input, Orders: orders, Times: times}
+}
+
+// splitNumericDate returns the date and the optional time of the input and the three numeric fields of the date.
+// It reports false when the input doesn't have the shape of a numeric date.
+func splitNumericDate(input string) (string, string, bool, []string, bool) {
+	date, clock, hasClock := strings.Cut(input, " ")
+	if !hasClock {
+		date, clock, hasClock = strings.Cut(input, "T")
+	}
+
+	sep := strings.IndexAny(date, "/.-")
+	if sep < 0 {
+		return "", "", false, nil, false
+	}
+	fields := strings.Split(date, date[sep:sep+1])
+	if len(fields) != 3 {
+		return "", "", false, nil, false
+	}
+	for _, field := range fields {
+		if field == "" || len(field) > 4 || strings.Trim(field, "0123456789") != "" {
+			return "", "", false, nil, false
+		}
+	}
+	return date, clock, hasClock, fields, true
+}
+
+// numericDate returns the date of the fields in the order and its layout, if it's valid.
+func numericDate(fields []string, sep byte, order DateOrder, loc *time.Location) (time.Time, string, bool) {
+	layout, ok := numericLayout(fields, sep, order)
+	if !ok {
+		return time.Time{}, "", false
+	}
+
+	year, month, day := fields[0], fields[1], fields[2]
+	switch order {
+	case DateOrderMDY:
+		month, day, year = fields[0], fields[1], fields[2]
+	case DateOrderDMY:
+		day, month, year = fields[0], fields[1], fields[2]
+	}
+
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	if len(year) == 2 {
+		y += 2000
+		if y >= 2069 {
+			y -= 100
+		}
+	}
+
+	t := time.Date(y, time.Month(m), d, 0, 0, 0, 0, loc)
+	if m < 1 || m > 12 || t.Day() != d {
+		return time.Time{}, "", false
+	}
+	return t, layout, true
+}
+
+// numericLayout returns the layout of the fields in the order, if their lengths fit the order.
+// Months and days with one digit use the elements without padding, e.g. "2/1/2006" for "4/3/2024".
+func numericLayout(fields []string, sep byte, order DateOrder) (string, bool) {
+	yearIndex, monthIndex, dayIndex := 2, 0, 1
+	switch order {
+	case DateOrderDMY:
+		yearIndex, monthIndex, dayIndex = 2, 1, 0
+	case DateOrderYMD:
+		yearIndex, monthIndex, dayIndex = 0, 1, 2
+	}
+	year, month, day := fields[yearIndex], fields[monthIndex], fields[dayIndex]
+
+	// years at the start always have four digits
+	if len(month) > 2 || len(day) > 2 || (len(year) != 4 && (len(year) != 2 || order == DateOrderYMD)) {
+		return "", false
+	}
+
+	var components [3]string
+	components[yearIndex], components[monthIndex], components[dayIndex] = "2006", "01", "02"
+	if len(year) == 2 {
+		components[yearIndex] = "06"
+	}
+	if len(month) == 1 {
+		components[monthIndex] = "1"
+	}
+	if len(day) == 1 {
+		components[dayIndex] = "2"
+	}
+	return strings.Join(components[:], string(sep)), true
+}
+
+// numericLayouts returns the layouts of all date orders which fit the input, e.g. "02.01.2006 15:04" for "31.02.2024 10:00".
+// Unlike ParseNumericDate, the dates don't have to be valid, which allows to diagnose them.
+func numericLayouts(input string) []string {
+	date, clock, hasClock, fields, ok := splitNumericDate(input)
+	if !ok {
+		return nil
+	}
+
+	clockLayout := ""
+	if hasClock {
+		clockLayout = input[len(date):len(date)+1] + numericTimes[0]
+		if strings.Count(clock, ":") > 1 {
+			clockLayout = input[len(date):len(date)+1] + numericTimes[1]
+		}
+	}
+
+	var layouts []string
+	for _, order := range []DateOrder{DateOrderMDY, DateOrderDMY, DateOrderYMD} {
+		if layout, ok := numericLayout(fields, date[len(fields[0])], order); ok {
+			layouts = append(layouts, layout+clockLayout)
+		}
+	}
+	return layouts
+}
@@ -0,0 +1,96 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseNumericDate(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		order          DateOrder
+		expected       time.Time
+		expectedLayout string
+		expectedErr    error
+	}{
+		{description: "ymd", input: "2024-01-25", expected: time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC), expectedLayout: "2006-01-02"},
+		{description: "ymd ignores preference", input: "2024/03/04", order: DateOrderDMY, expected: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), expectedLayout: "2006/01/02"},
+		{description: "unambiguous day first", input: "25.01.2019", expected: time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC), expectedLayout: "02.01.2006"},
+		{description: "unambiguous month first", input: "1/25/2019", order: DateOrderDMY, expected: time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC), expectedLayout: "1/02/2006"},
+		{description: "unpadded", input: "4/3/2024", order: DateOrderMDY, expected: time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC), expectedLayout: "1/2/2006"},
+		{description: "unpadded day first", input: "4.3.24 9:05", order: DateOrderDMY, expected: time.Date(2024, 3, 4, 9, 5, 0, 0, time.UTC), expectedLayout: "2.1.06 15:04"},
+		{description: "same date in both orders", input: "03/03/2024", expected: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), expectedLayout: "01/02/2006"},
+		{description: "mdy", input: "03/04/2024", order: DateOrderMDY, expected: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), expectedLayout: "01/02/2006"},
+		{description: "dmy", input: "03/04/2024", order: DateOrderDMY, expected: time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC), expectedLayout: "02/01/2006"},
+		{description: "short year", input: "25.01.69", expected: time.Date(1969, 1, 25, 0, 0, 0, 0, time.UTC), expectedLayout: "02.01.06"},
+		{description: "time", input: "25.01.2019 21:51", expected: time.Date(2019, 1, 25, 21, 51, 0, 0, time.UTC), expectedLayout: "02.01.2006 15:04"},
+		{description: "time with fraction", input: "2019-01-25T21:51:38.272", expected: time.Date(2019, 1, 25, 21, 51, 38, 272000000, time.UTC), expectedLayout: "2006-01-02T15:04:05"},
+		{
+			description: "ambiguous",
+			input:       "03/04/2024",
+			expectedErr: errors.New("ambiguous date '03/04/2024': 2024-03-04 (mdy) or 2024-04-03 (dmy), specify the date order"),
+		},
+		{
+			description: "ambiguous with impossible preference",
+			input:       "03-04-24",
+			order:       DateOrderYMD,
+			expectedErr: errors.New("ambiguous date '03-04-24': 2024-03-04 (mdy) or 2024-04-03 (dmy), specify the date order"),
+		},
+		{description: "invalid date", input: "31/02/2024", expectedErr: errors.New("failed to convert string to time: '31/02/2024' is no valid date")},
+		{description: "invalid time", input: "25.01.2019 25:00", expectedErr: errors.New("failed to convert string to time: '25.01.2019 25:00' has no valid time")},
+		{description: "mixed separators", input: "25.01/2019", expectedErr: errors.New("failed to convert string to time: '25.01/2019' is no numeric date")},
+		{description: "no date", input: "1595087205", expectedErr: errors.New("failed to convert string to time: '1595087205' is no numeric date")},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, layout, err := ParseNumericDate(tt.input, tt.order, time.UTC)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got, tt.expected)
+			equal(t, layout, tt.expectedLayout)
+
+			// the layout parses the input again
+			reparsed, err := time.ParseInLocation(layout, tt.input, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			equal(t, reparsed, tt.expected)
+		})
+	}
+}
+
+func TestAmbiguousDateError(t *testing.T) {
+	_, _, err := ParseFormatted("03/04/2024", time.UTC)
+
+	var ambiguous *AmbiguousDateError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("got %v, want an *AmbiguousDateError", err)
+	}
+	equal(t, ambiguous.Orders, []DateOrder{DateOrderMDY, DateOrderDMY})
+	equal(t, ambiguous.Times, []time.Time{time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC)})
+	equal(t, errors.Is(err, ErrParseFormatted), true)
+
+	got, layout, err := Parser{DateOrder: DateOrderDMY}.Parse("03/04/2024", time.UTC)
+	equal(t, err, nil)
+	equal(t, got, time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC))
+	equal(t, layout, "02/01/2006")
+}
+
+func TestParseDateOrder(t *testing.T) {
+	for _, order := range []DateOrder{DateOrderAuto, DateOrderMDY, DateOrderDMY, DateOrderYMD} {
+		got, err := ParseDateOrder(order.String())
+		equal(t, err, nil)
+		equal(t, got, order)
+	}
+
+	_, err := ParseDateOrder("myd")
+	equalError(t, err, errors.New("unknown date order 'myd' (use mdy, dmy or ymd)"))
+}
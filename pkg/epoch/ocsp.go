@@ -0,0 +1,125 @@
+package epoch
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// The structures of an OCSP response (RFC 6960, section 4.2.1), the signature isn't verified.
+type ocspResponse struct {
+	Status   asn1.Enumerated
+	Response ocspResponseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseBytes struct {
+	Type     asn1.ObjectIdentifier
+	Response []byte
+}
+
+type ocspBasicResponse struct {
+	ResponseData       ocspResponseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseData struct {
+	Version     int `asn1:"optional,default:0,explicit,tag:0"`
+	ResponderID asn1.RawValue
+	ProducedAt  time.Time `asn1:"generalized"`
+	Responses   []ocspSingleResponse
+	Extensions  []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type ocspSingleResponse struct {
+	CertID     ocspCertID
+	Good       asn1.Flag        `asn1:"tag:0,optional"`
+	Revoked    ocspRevokedInfo  `asn1:"tag:1,optional"`
+	Unknown    asn1.Flag        `asn1:"tag:2,optional"`
+	ThisUpdate time.Time        `asn1:"generalized"`
+	NextUpdate time.Time        `asn1:"generalized,explicit,tag:0,optional"`
+	Extensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type ocspCertID struct {
+	HashAlgorithm  pkix.AlgorithmIdentifier
+	IssuerNameHash []byte
+	IssuerKeyHash  []byte
+	SerialNumber   *big.Int
+}
+
+type ocspRevokedInfo struct {
+	RevocationTime time.Time       `asn1:"generalized"`
+	Reason         asn1.Enumerated `asn1:"explicit,tag:0,optional"`
+}
+
+// oidOCSPBasic is the type of basic OCSP responses, the only type defined by RFC 6960.
+var oidOCSPBasic = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+
+// ocspStatusNames are the names of the unsuccessful response statuses.
+var ocspStatusNames = map[asn1.Enumerated]string{
+	1: "malformedRequest",
+	2: "internalError",
+	3: "tryLater",
+	5: "sigRequired",
+	6: "unauthorized",
+}
+
+// isOCSPResponse reports whether the DER data has the structure of an OCSP response.
+func isOCSPResponse(data []byte) bool {
+	var response ocspResponse
+	rest, err := asn1.Unmarshal(data, &response)
+	return err == nil && len(rest) == 0
+}
+
+// parseOCSPValidity returns the validity of each certificate status in a DER encoded OCSP response.
+func parseOCSPValidity(data []byte) ([]Validity, error) {
+	var response ocspResponse
+	if _, err := asn1.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("%w: OCSP response: %v", ErrX509, err)
+	}
+	if response.Status != 0 {
+		status, ok := ocspStatusNames[response.Status]
+		if !ok {
+			status = fmt.Sprint(int(response.Status))
+		}
+		return nil, fmt.Errorf("%w: OCSP response status is %v", ErrX509, status)
+	}
+	if !response.Response.Type.Equal(oidOCSPBasic) {
+		return nil, fmt.Errorf("%w: unsupported OCSP response type %v", ErrX509, response.Response.Type)
+	}
+
+	var basic ocspBasicResponse
+	if _, err := asn1.Unmarshal(response.Response.Response, &basic); err != nil {
+		return nil, fmt.Errorf("%w: OCSP response: %v", ErrX509, err)
+	}
+	if len(basic.ResponseData.Responses) == 0 {
+		return nil, fmt.Errorf("%w: OCSP response without certificate statuses", ErrX509)
+	}
+
+	var validities []Validity
+	for _, single := range basic.ResponseData.Responses {
+		validity := Validity{
+			Subject: fmt.Sprintf("OCSP %X", single.CertID.SerialNumber),
+			Start:   Field{Name: "ThisUpdate", Time: single.ThisUpdate},
+			End:     Field{Name: "NextUpdate", Time: single.NextUpdate},
+			Events:  []Field{{Name: "ProducedAt", Time: basic.ResponseData.ProducedAt}},
+		}
+		if single.NextUpdate.IsZero() {
+			validity.End.Note = "no next update"
+		}
+
+		switch {
+		case bool(single.Good):
+		case bool(single.Unknown):
+			validity.Start.Note = "unknown certificate status"
+		default:
+			validity.Events = append(validity.Events, Field{Name: "RevocationTime", Time: single.Revoked.RevocationTime})
+		}
+		validities = append(validities, validity)
+	}
+	return validities, nil
+}
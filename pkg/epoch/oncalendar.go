@@ -0,0 +1,285 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrParseOnCalendar is returned when a systemd calendar expression is invalid.
+var ErrParseOnCalendar = errors.New("failed to parse calendar expression")
+
+// onCalendarShorthands are the normalized forms of the systemd shorthands (systemd.time(7)).
+var onCalendarShorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+}
+
+// onCalendarWeekdays are the weekday names of systemd, Sunday is 7 to allow ranges such as "Mon..Sun".
+var onCalendarWeekdays = map[string]int{
+	"mon": 1, "monday": 1,
+	"tue": 2, "tuesday": 2,
+	"wed": 3, "wednesday": 3,
+	"thu": 4, "thursday": 4,
+	"fri": 5, "friday": 5,
+	"sat": 6, "saturday": 6,
+	"sun": 7, "sunday": 7,
+}
+
+// ParseOnCalendar parses a systemd calendar expression as used by OnCalendar= of timers,
+// "[Weekday] [[Year-]Month-Day] [Hour:Minute[:Second]] [Timezone]", e.g. "Mon..Fri *-*-* 09:00:00 Europe/Berlin".
+// The components support lists ("Sat,Sun", "1,15"), ranges ("Mon..Fri", "9..17"), repetitions ("*:0/15")
+// and days counted from the end of the month ("*-02~01" is the last day of February).
+// Shorthands such as "daily" or "weekly" are supported as well.
+//
+// A missing date matches every day, a missing time is midnight. The expression is evaluated in the given location
+// (Local when nil), unless it ends with a timezone. Unlike cron, the weekday and the date both have to match.
+func ParseOnCalendar(expr string, loc *time.Location) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", ErrParseOnCalendar)
+	}
+
+	// e.g. "Mon *-*-* 09:00 Europe/Berlin", dates and times aren't valid timezones
+	if len(fields) > 1 {
+		if tz, err := time.LoadLocation(fields[len(fields)-1]); err == nil {
+			loc, fields = tz, fields[:len(fields)-1]
+		}
+	}
+
+	if len(fields) == 1 {
+		if shorthand, ok := onCalendarShorthands[strings.ToLower(fields[0])]; ok {
+			fields = strings.Fields(shorthand)
+		}
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+	spec := &calendarSpec{loc: loc, weekdays: 0x7f}
+	weekday, date, clock := "", "*-*-*", "00:00:00"
+
+	// the components are optional but ordered: weekday, date, time
+	const (
+		componentWeekday = iota + 1
+		componentDate
+		componentTime
+	)
+	last := 0
+	for _, field := range fields {
+		component := componentDate
+		switch {
+		case strings.Contains(field, ":"):
+			component, clock = componentTime, field
+		case unicode.IsLetter([]rune(field)[0]):
+			component, weekday = componentWeekday, field
+		case strings.ContainsAny(field, "-~"):
+			date = field
+		default:
+			return nil, fmt.Errorf("%w: unexpected '%v'", ErrParseOnCalendar, field)
+		}
+		if component <= last {
+			return nil, fmt.Errorf("%w: unexpected '%v'", ErrParseOnCalendar, field)
+		}
+		last = component
+	}
+
+	if weekday != "" {
+		weekdays, err := parseOnCalendarWeekdays(weekday)
+		if err != nil {
+			return nil, fmt.Errorf("%w: weekday: %v", ErrParseOnCalendar, err)
+		}
+		spec.weekdays = weekdays
+	}
+
+	if err := spec.parseOnCalendarDate(date); err != nil {
+		return nil, fmt.Errorf("%w: date: %v", ErrParseOnCalendar, err)
+	}
+	if err := spec.parseOnCalendarTime(clock); err != nil {
+		return nil, fmt.Errorf("%w: time: %v", ErrParseOnCalendar, err)
+	}
+	return spec, nil
+}
+
+func parseOnCalendarWeekdays(field string) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		// systemd accepts "Mon-Fri" as well
+		separator := ".."
+		if !strings.Contains(part, separator) {
+			separator = "-"
+		}
+		lowName, highName, isRange := strings.Cut(part, separator)
+
+		low, ok := onCalendarWeekdays[strings.ToLower(lowName)]
+		if !ok {
+			return 0, fmt.Errorf("invalid value '%v'", lowName)
+		}
+		high := low
+		if isRange {
+			if high, ok = onCalendarWeekdays[strings.ToLower(highName)]; !ok {
+				return 0, fmt.Errorf("invalid value '%v'", highName)
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range '%v'", part)
+			}
+		}
+
+		for i := low; i <= high; i++ {
+			mask |= 1 << uint(i%7)
+		}
+	}
+	return mask, nil
+}
+
+// parseOnCalendarDate parses "Year-Month-Day" or "Month-Day", with "~" instead of the last "-" for days from the end.
+func (c *calendarSpec) parseOnCalendarDate(date string) error {
+	separator := "-"
+	if strings.Contains(date, "~") {
+		separator, c.daysFromEnd = "~", true
+	}
+	rest, day, found := cutLast(date, separator)
+	if !found {
+		return fmt.Errorf("invalid date '%v'", date)
+	}
+	year, month, hasYear := strings.Cut(rest, "-")
+	if !hasYear {
+		year, month = "*", rest
+	}
+
+	var err error
+	if hasYear && year != "*" {
+		years, err := parseOnCalendarValues(year, 1970, 2199, false)
+		if err != nil {
+			return err
+		}
+		c.years = map[int]bool{}
+		for _, y := range years {
+			c.years[y] = true
+		}
+	}
+	if c.months, err = parseOnCalendarMask(month, 1, 12, false); err != nil {
+		return err
+	}
+	c.days, err = parseOnCalendarMask(day, 1, 31, c.daysFromEnd)
+	return err
+}
+
+// parseOnCalendarTime parses "Hour:Minute" or "Hour:Minute:Second".
+func (c *calendarSpec) parseOnCalendarTime(clock string) error {
+	parts := strings.Split(clock, ":")
+	switch len(parts) {
+	case 2:
+		parts = append(parts, "00")
+	case 3:
+	default:
+		return fmt.Errorf("invalid time '%v'", clock)
+	}
+
+	var err error
+	if c.hours, err = parseOnCalendarMask(parts[0], 0, 23, false); err != nil {
+		return err
+	}
+	if c.minutes, err = parseOnCalendarMask(parts[1], 0, 59, false); err != nil {
+		return err
+	}
+	c.seconds, err = parseOnCalendarMask(parts[2], 0, 59, false)
+	return err
+}
+
+func parseOnCalendarMask(field string, min, max int, reverse bool) (uint64, error) {
+	values, err := parseOnCalendarValues(field, min, max, reverse)
+	if err != nil {
+		return 0, err
+	}
+	var mask uint64
+	for _, value := range values {
+		mask |= 1 << uint(value)
+	}
+	return mask, nil
+}
+
+// parseOnCalendarValues returns the values of a component with lists, ranges ("1..5") and repetitions ("0/15").
+// Repetitions of reversed components count down, e.g. "7/1" of days from the end are the last 7 days.
+func parseOnCalendarValues(field string, min, max int, reverse bool) ([]int, error) {
+	var values []int
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid repetition '%v'", stepPart)
+			}
+		}
+
+		var low, high int
+		switch {
+		case rangePart == "*":
+			low, high = min, max
+		case strings.Contains(rangePart, ".."):
+			lowPart, highPart, _ := strings.Cut(rangePart, "..")
+			var err error
+			if low, err = parseOnCalendarValue(lowPart, min, max); err != nil {
+				return nil, err
+			}
+			if high, err = parseOnCalendarValue(highPart, min, max); err != nil {
+				return nil, err
+			}
+			if low > high {
+				return nil, fmt.Errorf("invalid range '%v'", rangePart)
+			}
+		default:
+			var err error
+			if low, err = parseOnCalendarValue(rangePart, min, max); err != nil {
+				return nil, err
+			}
+			high = low
+			if hasStep && reverse {
+				for i := low; i >= min; i -= step {
+					values = append(values, i)
+				}
+				continue
+			}
+			if hasStep {
+				high = max
+			}
+		}
+
+		for i := low; i <= high; i += step {
+			values = append(values, i)
+		}
+	}
+	return values, nil
+}
+
+func parseOnCalendarValue(s string, min, max int) (int, error) {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%v'", s)
+	}
+	if value < min || value > max {
+		return 0, fmt.Errorf("value %v out of range (%v-%v)", value, min, max)
+	}
+	return value, nil
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (string, string, bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,201 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseOnCalendar(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Friday
+	given := time.Date(2024, 3, 29, 10, 0, 0, 0, berlin)
+
+	testCases := []struct {
+		description string
+		expr        string
+		count       int
+		expected    []time.Time
+		expectedErr error
+	}{
+		{
+			description: "weekdays with timezone",
+			expr:        "Mon..Fri *-*-* 09:00:00 Asia/Tokyo",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 4, 1, 9, 0, 0, 0, tokyo),
+				time.Date(2024, 4, 2, 9, 0, 0, 0, tokyo),
+			},
+		},
+		{
+			description: "weekend without date",
+			expr:        "Sat,Sunday 10:30",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 3, 30, 10, 30, 0, 0, berlin),
+				time.Date(2024, 3, 31, 10, 30, 0, 0, berlin),
+			},
+		},
+		{
+			description: "range until sunday",
+			expr:        "Fri..Sun 08:00",
+			count:       3,
+			expected: []time.Time{
+				time.Date(2024, 3, 30, 8, 0, 0, 0, berlin),
+				time.Date(2024, 3, 31, 8, 0, 0, 0, berlin),
+				time.Date(2024, 4, 5, 8, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "repetition",
+			expr:        "*:0/20",
+			count:       3,
+			expected: []time.Time{
+				time.Date(2024, 3, 29, 10, 20, 0, 0, berlin),
+				time.Date(2024, 3, 29, 10, 40, 0, 0, berlin),
+				time.Date(2024, 3, 29, 11, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "date without time",
+			expr:        "2024-04..05-01",
+			count:       3,
+			expected: []time.Time{
+				time.Date(2024, 4, 1, 0, 0, 0, 0, berlin),
+				time.Date(2024, 5, 1, 0, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "month and day",
+			expr:        "12-24 18:00",
+			count:       1,
+			expected:    []time.Time{time.Date(2024, 12, 24, 18, 0, 0, 0, berlin)},
+		},
+		{
+			description: "last day of the month",
+			expr:        "*-*~01 23:59:59",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 3, 31, 23, 59, 59, 0, berlin),
+				time.Date(2024, 4, 30, 23, 59, 59, 0, berlin),
+			},
+		},
+		{
+			description: "last monday of may",
+			expr:        "Mon *-05~07/1",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 5, 27, 0, 0, 0, 0, berlin),
+				time.Date(2025, 5, 26, 0, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "weekday and date both match",
+			expr:        "Fri *-*-13",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 9, 13, 0, 0, 0, 0, berlin),
+				time.Date(2024, 12, 13, 0, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "shorthand",
+			expr:        "weekly",
+			count:       1,
+			expected:    []time.Time{time.Date(2024, 4, 1, 0, 0, 0, 0, berlin)},
+		},
+		{
+			description: "shorthand with timezone",
+			expr:        "daily UTC",
+			count:       1,
+			expected:    []time.Time{time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "previous",
+			expr:        "quarterly",
+			count:       -2,
+			expected: []time.Time{
+				time.Date(2024, 1, 1, 0, 0, 0, 0, berlin),
+				time.Date(2023, 10, 1, 0, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "past year",
+			expr:        "2020-*-* 00:00",
+			count:       1,
+			expectedErr: errors.New("no occurrences"),
+		},
+		{
+			description: "skipped by dst",
+			expr:        "*-03-30..31 02:30",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 3, 30, 2, 30, 0, 0, berlin),
+				// 2024-03-31 and 2025-03-30 02:30 don't exist in Berlin
+				time.Date(2025, 3, 31, 2, 30, 0, 0, berlin),
+			},
+		},
+		{
+			description: "empty",
+			expr:        " ",
+			expectedErr: errors.New("failed to parse calendar expression: empty expression"),
+		},
+		{
+			description: "wrong order",
+			expr:        "09:00 Mon",
+			expectedErr: errors.New("failed to parse calendar expression: unexpected 'Mon'"),
+		},
+		{
+			description: "symbol",
+			expr:        "_ 09:00",
+			expectedErr: errors.New("failed to parse calendar expression: unexpected '_'"),
+		},
+		{
+			description: "unknown weekday",
+			expr:        "Mon..Fre 09:00",
+			expectedErr: errors.New("failed to parse calendar expression: weekday: invalid value 'Fre'"),
+		},
+		{
+			description: "out of range",
+			expr:        "*-*-* 24:00",
+			expectedErr: errors.New("failed to parse calendar expression: time: value 24 out of range (0-23)"),
+		},
+		{
+			description: "invalid range",
+			expr:        "*-12..10-01",
+			expectedErr: errors.New("failed to parse calendar expression: date: invalid range '12..10'"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			schedule, err := ParseOnCalendar(tt.expr, berlin)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				equal(t, errors.Is(err, ErrParseOnCalendar), true)
+				return
+			}
+
+			got, err := Occurrences(schedule, given, tt.count)
+			if err != nil || tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				equal(t, errors.Is(err, ErrNoOccurrences), true)
+				return
+			}
+			equal(t, len(got), len(tt.expected))
+			for i := range got {
+				if !got[i].Equal(tt.expected[i]) {
+					t.Fatalf("occurrence %v: got %v, want %v", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ParseError is returned when no layout can parse an input. It wraps ErrParseFormatted.
+// The closest layout is only determined when the error is inspected, as it tries all layouts again.
+type ParseError struct {
+	Input string
+
+	parser Parser
+	tz     *time.Location
+
+	once      sync.Once
+	diagnosis diagnosis
+}
+
+// diagnosis is the result of trying all layouts for a ParseError.
+type diagnosis struct {
+	layouts []string
+	closest string
+	offset  int
+	reason  string
+}
+
+func (e *ParseError) Error() string {
+	d := e.diagnose()
+	if d.closest == "" {
+		return fmt.Sprintf("%v: '%v' doesn't match any of %v layouts", ErrParseFormatted, e.Input, len(d.layouts))
+	}
+	if d.offset < 0 {
+		return fmt.Sprintf("%v: '%v' looks like %v but %v", ErrParseFormatted, e.Input, layoutTitle(d.closest), d.reason)
+	}
+	return fmt.Sprintf("%v: '%v' looks like %v but %v at column %v", ErrParseFormatted, e.Input, layoutTitle(d.closest), d.reason, e.Column())
+}
+
+// Unwrap returns ErrParseFormatted.
+func (e *ParseError) Unwrap() error {
+	return ErrParseFormatted
+}
+
+// Layouts returns the names of all tried layouts in order (see Parser.Parse), numeric dates are tried last.
+func (e *ParseError) Layouts() []string {
+	return e.diagnose().layouts
+}
+
+// Closest returns the name of the layout which matched the longest beginning of the input,
+// empty when no layout matched anything.
+func (e *ParseError) Closest() string {
+	return e.diagnose().closest
+}
+
+// Offset returns the byte offset in the input where the closest layout failed,
+// -1 when the input doesn't have a position, e.g. for February 30.
+func (e *ParseError) Offset() int {
+	return e.diagnose().offset
+}
+
+// Reason describes why the closest layout failed, e.g. "the UTC offset is malformed".
+func (e *ParseError) Reason() string {
+	return e.diagnose().reason
+}
+
+// Column returns the position in characters where the closest layout failed, starting at 1.
+// It's 0 without a closest layout or a position (see Offset).
+func (e *ParseError) Column() int {
+	d := e.diagnose()
+	if d.closest == "" || d.offset < 0 {
+		return 0
+	}
+	return utf8.RuneCountInString(e.Input[:d.offset]) + 1
+}
+
+// builtinTitles are the names of the built-in layouts in errors.
+var builtinTitles = map[string]string{
+	time.RFC1123:     "RFC1123",
+	time.RFC1123Z:    "RFC1123Z",
+	time.RFC3339:     "RFC3339",
+	time.RFC3339Nano: "RFC3339Nano",
+	time.RFC822:      "RFC822",
+	time.RFC822Z:     "RFC822Z",
+	time.RFC850:      "RFC850",
+	time.ANSIC:       "ANSIC",
+	time.UnixDate:    "UnixDate",
+	time.RubyDate:    "RubyDate",
+	time.Kitchen:     "Kitchen",
+	time.Stamp:       "Stamp",
+	time.StampMilli:  "StampMilli",
+	time.StampMicro:  "StampMicro",
+	time.StampNano:   "StampNano",
+	TimeFormatHTTP:   "HTTP",
+	TimeFormatGo:     "Go",
+	TimeFormatSimple: "'2006-01-02 15:04:05'",
+}
+
+func layoutTitle(layout string) string {
+	if title, ok := builtinTitles[layout]; ok {
+		return title
+	}
+	return "'" + layout + "'"
+}
+
+// goElementNames are the descriptions of Go layout elements in errors.
+var goElementNames = map[string]string{
+	"2006": "year", "06": "year",
+	"01": "month", "1": "month", "Jan": "month", "January": "month",
+	"02": "day", "2": "day", "_2": "day",
+	"002": "day of the year", "__2": "day of the year",
+	"Mon": "weekday", "Monday": "weekday",
+	"15": "hour", "03": "hour", "3": "hour",
+	"04": "minute", "4": "minute",
+	"05": "second", "5": "second",
+	"PM": "AM/PM", "pm": "AM/PM",
+	"MST": "timezone abbreviation",
+}
+
+// mismatch returns the byte offset, the matched length and the reason why a layout couldn't parse the input.
+// The matched length includes numbers which are out of range, it's the length of the input when the offset is -1.
+func mismatch(input string, err error) (int, int, string, bool) {
+	var goErr *time.ParseError
+	if errors.As(err, &goErr) {
+		offset := len(goErr.Value) - len(goErr.ValueElem)
+		if offset < 0 || offset > len(input) {
+			return 0, 0, "", false
+		}
+
+		message := strings.TrimPrefix(goErr.Message, ": ")
+		switch {
+		case strings.HasPrefix(message, "extra text"):
+			return offset, offset, fmt.Sprintf("has unexpected text '%v'", goErr.ValueElem), true
+		case strings.HasSuffix(message, " out of range") && goErr.LayoutElem == "":
+			// e.g. February 30, checked after parsing the whole input
+			return -1, len(input), "the " + strings.TrimSuffix(message, " out of range") + " doesn't exist", true
+		case strings.HasSuffix(message, " out of range"):
+			// the offset is after the number
+			start := strings.LastIndexFunc(input[:offset], func(r rune) bool { return r < '0' || r > '9' }) + 1
+			return start, offset, "the " + strings.TrimSuffix(message, " out of range") + " is out of range", true
+		case message != "":
+			return offset, offset, message, true
+		case strings.HasPrefix(goErr.LayoutElem, "Z") || strings.HasPrefix(goErr.LayoutElem, "-07"):
+			return offset, offset, "the UTC offset is malformed", true
+		case strings.HasPrefix(goErr.LayoutElem, ".") || strings.HasPrefix(goErr.LayoutElem, ","):
+			return offset, offset, "the fractional second is malformed", true
+		}
+		if element, ok := goElementNames[goErr.LayoutElem]; ok {
+			return offset, offset, fmt.Sprintf("the %v is malformed", element), true
+		}
+		return offset, offset, fmt.Sprintf("expected '%v'", goErr.LayoutElem), true
+	}
+
+	var mismatchErr *mismatchError
+	if errors.As(err, &mismatchErr) {
+		return mismatchErr.offset, mismatchErr.matched, mismatchErr.err.Error(), true
+	}
+	return 0, 0, "", false
+}
+
+// parseError returns a *ParseError for inputs which don't match any layout, other errors are returned as is.
+func (p Parser) parseError(input string, tz *time.Location, err error) error {
+	if err != ErrParseFormatted {
+		return err
+	}
+	return &ParseError{Input: input, parser: p, tz: tz}
+}
+
+// diagnose tries all layouts once, as the closest layout might not be a candidate of the input.
+func (e *ParseError) diagnose() *diagnosis {
+	e.once.Do(func() {
+		e.diagnosis = e.parser.diagnose(e.Input, e.tz)
+	})
+	return &e.diagnosis
+}
+
+func (p Parser) diagnose(input string, tz *time.Location) diagnosis {
+	var d diagnosis
+	var (
+		closest        = 0 // matched length of the closest layout
+		closestInvalid = false
+	)
+	try := func(name string, err error) {
+		d.layouts = append(d.layouts, name)
+		offset, matched, reason, ok := mismatch(input, err)
+		if !ok {
+			return
+		}
+		// with the same matched length, invalid values such as February 30 or a day 32 are closer
+		// than a layout which expected something else, e.g. a UTC offset at the end of the input
+		invalid := offset < 0 || matched > offset
+		if matched > closest || (matched == closest && d.closest != "" && invalid && !closestInvalid) {
+			d.closest, d.offset, d.reason = name, offset, reason
+			closest, closestInvalid = matched, invalid
+		}
+	}
+
+	for _, layout := range p.Layouts {
+		if p.Locale != nil {
+			layout = layout.WithLocale(*p.Locale)
+		}
+		_, err := layout.Parse(input, tz)
+		try(layout.String(), err)
+	}
+
+	if p.Locale != nil {
+		for _, layout := range p.Locale.Layouts {
+			_, err := p.Locale.Parse(layout, input, tz)
+			try(layout, err)
+		}
+	}
+
+	for _, layout := range builtinLayouts {
+		_, err := parseBuiltin(layout, input, tz)
+		try(layout, err)
+	}
+
+	// e.g. "02.01.2006" for "31.02.2024", ParseNumericDate doesn't have positions
+	for _, layout := range numericLayouts(input) {
+		_, err := time.ParseInLocation(layout, input, tz)
+		try(layout, err)
+	}
+
+	return d
+}
@@ -0,0 +1,98 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseError(t *testing.T) {
+	custom, err := ParseLayout("%d.%m.%Y %H:%M")
+	equal(t, err, nil)
+	customDate, err := ParseLayout("%d.%m.%Y")
+	equal(t, err, nil)
+
+	testCases := []struct {
+		description     string
+		parser          Parser
+		input           string
+		expectedClosest string
+		expectedColumn  int
+		expectedErr     error
+	}{
+		{
+			description:     "malformed offset",
+			input:           "2024-01-01T10:00:00+0x:00",
+			expectedClosest: time.RFC3339,
+			expectedColumn:  20,
+			expectedErr:     errors.New("failed to convert string to time: '2024-01-01T10:00:00+0x:00' looks like RFC3339 but the UTC offset is malformed at column 20"),
+		},
+		{
+			description:     "literal",
+			input:           "2024-01-01 10:00:00 +0100 CET x",
+			expectedClosest: TimeFormatGo,
+			expectedColumn:  30,
+			expectedErr:     errors.New("failed to convert string to time: '2024-01-01 10:00:00 +0100 CET x' looks like Go but has unexpected text ' x' at column 30"),
+		},
+		{
+			description:     "out of range",
+			input:           "2024-01-01T25:00:00Z",
+			expectedClosest: time.RFC3339,
+			expectedColumn:  12,
+			expectedErr:     errors.New("failed to convert string to time: '2024-01-01T25:00:00Z' looks like RFC3339 but the hour is out of range at column 12"),
+		},
+		{
+			description:     "no position",
+			input:           "Mon, 30 Feb 2024 10:00:00 GMT",
+			expectedClosest: time.RFC1123,
+			expectedErr:     errors.New("failed to convert string to time: 'Mon, 30 Feb 2024 10:00:00 GMT' looks like RFC1123 but the day doesn't exist"),
+		},
+		{
+			description:     "custom layout",
+			parser:          Parser{Layouts: []Layout{custom}},
+			input:           "25.01.2019 2x:51",
+			expectedClosest: "%d.%m.%Y %H:%M",
+			expectedColumn:  13,
+			expectedErr:     errors.New("failed to convert string to time: '25.01.2019 2x:51' looks like '%d.%m.%Y %H:%M' but expected ':' at column 13"),
+		},
+		{
+			description:     "no position before partial match",
+			input:           "2024-02-30 10:00:00",
+			expectedClosest: TimeFormatSimple,
+			expectedErr:     errors.New("failed to convert string to time: '2024-02-30 10:00:00' looks like '2006-01-02 15:04:05' but the day doesn't exist"),
+		},
+		{
+			description:     "numeric date",
+			input:           "31.02.2024",
+			expectedClosest: "02.01.2006",
+			expectedErr:     errors.New("failed to convert string to time: '31.02.2024' looks like '02.01.2006' but the day doesn't exist"),
+		},
+		{
+			description:     "custom layout out of range",
+			parser:          Parser{Layouts: []Layout{customDate}},
+			input:           "32.01.2024",
+			expectedClosest: "%d.%m.%Y",
+			expectedColumn:  1,
+			expectedErr:     errors.New("failed to convert string to time: '32.01.2024' looks like '%d.%m.%Y' but value 32 out of range for '%d' (1-31) at column 1"),
+		},
+		{
+			description: "no closest",
+			input:       "yesterday",
+			expectedErr: errors.New("failed to convert string to time: 'yesterday' doesn't match any of 18 layouts"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			_, _, err := tt.parser.Parse(tt.input, time.UTC)
+			equalError(t, err, tt.expectedErr)
+			equal(t, errors.Is(err, ErrParseFormatted), true)
+
+			var parseErr *ParseError
+			equal(t, errors.As(err, &parseErr), true)
+			equal(t, parseErr.Closest(), tt.expectedClosest)
+			equal(t, len(parseErr.Layouts()), len(tt.parser.Layouts)+len(builtinLayouts)+len(numericLayouts(tt.input)))
+			equal(t, parseErr.Column(), tt.expectedColumn)
+		})
+	}
+}
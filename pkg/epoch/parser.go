@@ -0,0 +1,284 @@
+package epoch
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrInvalidLayout is returned when a layout can't be used.
+var ErrInvalidLayout = errors.New("invalid layout")
+
+// layoutTranslators convert patterns with a prefix such as "java:" to Go layouts.
+var layoutTranslators = map[string]func(string) (string, error){
+	"go":     func(layout string) (string, error) { return layout, nil },
+	"java":   JavaLayout,
+	"dotnet": DotNetLayout,
+	"moment": MomentLayout,
+	"luxon":  LuxonLayout,
+}
+
+// Layout is a custom format for parsing and formatting times.
+// It is either a Go layout, a simple format or a strftime format.
+type Layout struct {
+	// Name is the name of the layout, e.g. from a layouts file.
+	Name string
+
+	spec     string
+	goLayout string
+	strftime string
+	simple   *SimpleFormat
+	locale   *Locale
+}
+
+// ParseLayout creates a layout from its specification:
+//
+//   - "go:", "java:", "dotnet:", "moment:" and "luxon:" prefixes for patterns converted to Go layouts (see JavaLayout etc.)
+//   - "simple:" prefix or text containing "{" for simple formats (see ParseSimpleFormat)
+//   - "strftime:" prefix or text containing "%" for strftime formats (see Strftime)
+//   - any other text is a Go layout, e.g. "02.01.2006 15:04"
+func ParseLayout(spec string) (Layout, error) {
+	l := Layout{spec: spec}
+
+	prefix, pattern, _ := strings.Cut(spec, ":")
+	prefix = strings.ToLower(prefix)
+
+	simple := func(format string) (Layout, error) {
+		f, err := ParseSimpleFormat(format)
+		if err != nil {
+			return Layout{}, err
+		}
+		l.simple = &f
+		return l, nil
+	}
+
+	strftime := func(format string) (Layout, error) {
+		if _, err := tokenizeStrftime(format); err != nil {
+			return Layout{}, err
+		}
+		l.strftime = format
+		return l, nil
+	}
+
+	if translate, ok := layoutTranslators[prefix]; ok {
+		layout, err := translate(pattern)
+		if err != nil {
+			return Layout{}, err
+		}
+		l.goLayout = layout
+		return l, nil
+	}
+
+	switch {
+	case prefix == "simple":
+		return simple(pattern)
+	case prefix == "strftime":
+		return strftime(pattern)
+	case strings.Contains(spec, "%"):
+		return strftime(spec)
+	case strings.Contains(spec, "{"):
+		return simple(spec)
+	}
+
+	if layoutProbe.Format(spec) == spec {
+		return Layout{}, fmt.Errorf("%w: '%v' doesn't contain any Go layout elements", ErrInvalidLayout, spec)
+	}
+	l.goLayout = spec
+	return l, nil
+}
+
+// String returns the name of the layout or its specification when it has no name.
+func (l Layout) String() string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return l.spec
+}
+
+// WithLocale returns a copy of the layout which parses and formats the names of months and weekdays
+// of the locale instead of the English names.
+func (l Layout) WithLocale(locale Locale) Layout {
+	l.locale = &locale
+	return l
+}
+
+// Parse parses the value with the layout. The location is used for values without a time zone.
+func (l Layout) Parse(value string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	layout := l.goLayout
+	switch {
+	case l.strftime != "" && l.locale != nil:
+		return l.locale.Strptime(value, l.strftime, loc)
+	case l.strftime != "":
+		return Strptime(value, l.strftime, loc)
+	case l.simple != nil:
+		var err error
+		layout, err = l.simple.Layout()
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if l.locale != nil {
+		return l.locale.Parse(layout, value, loc)
+	}
+	return time.ParseInLocation(layout, value, loc)
+}
+
+// Format returns the time formatted with the layout.
+func (l Layout) Format(t time.Time) string {
+	locale := english
+	if l.locale != nil {
+		locale = *l.locale
+	}
+
+	switch {
+	case l.strftime != "":
+		// the format was validated when creating the layout
+		s, _ := locale.Strftime(t, l.strftime)
+		return s
+	case l.simple != nil:
+		return l.simple.formatLocale(t, locale)
+	}
+	return locale.Format(t, l.goLayout)
+}
+
+// LoadLayouts reads named layouts, one "name: layout" per line (see ParseLayout), e.g.:
+//
+//	# our nginx logs
+//	nginx: 02/Jan/2006:15:04:05 -0700
+//	app: "%Y-%m-%d %H:%M:%S,%3N"
+//
+// Layouts may be quoted. Empty lines and lines starting with "#" are ignored.
+func LoadLayouts(r io.Reader) ([]Layout, error) {
+	var (
+		layouts []Layout
+		names   = map[string]bool{}
+		line    = 0
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		name, spec, found := strings.Cut(text, ":")
+		name, spec = strings.TrimSpace(name), strings.TrimSpace(spec)
+		if !found || name == "" || spec == "" {
+			return nil, fmt.Errorf("line %v: expected 'name: layout': '%v'", line, text)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("line %v: duplicate layout '%v'", line, name)
+		}
+		names[name] = true
+
+		if len(spec) >= 2 && (spec[0] == '"' || spec[0] == '\'') && spec[len(spec)-1] == spec[0] {
+			spec = spec[1 : len(spec)-1]
+		}
+
+		layout, err := ParseLayout(spec)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: layout '%v': %w", line, name, err)
+		}
+		layout.Name = name
+		layouts = append(layouts, layout)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read layouts: %w", err)
+	}
+
+	return layouts, nil
+}
+
+// Parser parses formatted times with custom layouts before the built-in layouts of ParseFormatted.
+// The zero value only uses the built-in layouts.
+type Parser struct {
+	// Layouts are tried in order before the built-in layouts.
+	Layouts []Layout
+	// Locale is used for the names in the custom layouts, its common layouts
+	// are tried after the custom layouts. Nil uses English names.
+	Locale *Locale
+	// DateOrder is the preferred order of numeric dates such as "03/04/2024" (see ParseNumericDate).
+	DateOrder DateOrder
+}
+
+// Parse returns the time of the first matching layout and its name (see Layout.String for custom layouts).
+// TZ is only used for inputs without a specific timezone.
+func (p Parser) Parse(input string, tz *time.Location) (time.Time, string, error) {
+	t, name, _, err := p.parse(input, tz)
+	if err != nil {
+		return time.Time{}, "", p.parseError(input, tz, err)
+	}
+	return t, name, nil
+}
+
+// parseFunc parses an input with a single layout.
+type parseFunc func(input string, tz *time.Location) (time.Time, error)
+
+// parse returns the time, the name of the matching layout and its parse function.
+func (p Parser) parse(input string, tz *time.Location) (time.Time, string, parseFunc, error) {
+	for _, layout := range p.Layouts {
+		if p.Locale != nil {
+			layout = layout.WithLocale(*p.Locale)
+		}
+		if t, err := layout.Parse(input, tz); err == nil {
+			return t, layout.String(), layout.Parse, nil
+		}
+	}
+
+	if p.Locale != nil {
+		locale := *p.Locale
+		for _, layout := range locale.Layouts {
+			if t, err := locale.Parse(layout, input, tz); err == nil {
+				return t, layout, func(input string, tz *time.Location) (time.Time, error) {
+					return locale.Parse(layout, input, tz)
+				}, nil
+			}
+		}
+	}
+
+	t, layout, err := parseFormatted(input, tz, p.DateOrder)
+	if err != nil {
+		return time.Time{}, "", nil, err
+	}
+	// numeric dates return Go layouts as well
+	return t, layout, func(input string, tz *time.Location) (time.Time, error) {
+		return parseBuiltin(layout, input, tz)
+	}, nil
+}
+
+// StickyParser is a Parser for streams of inputs in the same layout, such as log lines.
+// It remembers the layout of the last parsed input and tries it first, before all other layouts.
+// Numeric dates which would be ambiguous (see ParseNumericDate) are parsed in the order of the last date.
+// A StickyParser must not be used concurrently.
+type StickyParser struct {
+	Parser
+
+	last   parseFunc
+	layout string
+}
+
+// Parse returns the time and the name of the matching layout (see Parser.Parse).
+func (p *StickyParser) Parse(input string, tz *time.Location) (time.Time, string, error) {
+	if p.last != nil {
+		if t, err := p.last(input, tz); err == nil {
+			return t, p.layout, nil
+		}
+	}
+
+	t, layout, parse, err := p.Parser.parse(input, tz)
+	if err != nil {
+		return time.Time{}, "", p.Parser.parseError(input, tz, err)
+	}
+	p.last, p.layout = parse, layout
+	return t, layout, nil
+}
@@ -0,0 +1,137 @@
+package epoch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLayout(t *testing.T) {
+	given := time.Date(2019, 1, 25, 21, 51, 38, 272000000, time.UTC)
+
+	testCases := []struct {
+		description string
+		spec        string
+		expected    string
+		expectedErr error
+	}{
+		{description: "go", spec: "02.01.2006 15:04", expected: "25.01.2019 21:51"},
+		{description: "go/prefix", spec: "go:2006", expected: "2019"},
+		{description: "java", spec: "java:dd.MM.yyyy HH:mm:ss.SSS", expected: "25.01.2019 21:51:38.272"},
+		{description: "strftime", spec: "%d.%m.%Y %H:%M", expected: "25.01.2019 21:51"},
+		{description: "strftime/prefix", spec: "strftime:%Y", expected: "2019"},
+		{description: "simple", spec: "{DD}.{MM}.{YYYY} W{WW}", expected: "25.01.2019 W04"},
+		{description: "simple/prefix", spec: "simple:{YYYY}", expected: "2019"},
+		{description: "no elements", spec: "today", expectedErr: errors.New("invalid layout: 'today' doesn't contain any Go layout elements")},
+		{description: "invalid strftime", spec: "%Q", expectedErr: errors.New("invalid strftime format: unknown conversion '%Q'")},
+		{description: "invalid simple", spec: "{Q", expectedErr: errors.New("invalid simple format '{Q': unterminated '{' at position 0")},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			layout, err := ParseLayout(tt.spec)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, layout.Format(given), tt.expected)
+			equal(t, layout.String(), tt.spec)
+		})
+	}
+}
+
+func TestLoadLayouts(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		layouts, err := LoadLayouts(strings.NewReader(`
+# our nginx logs
+nginx: 02/Jan/2006:15:04:05 -0700
+app: "%Y-%m-%d %H:%M:%S,%3N"
+`))
+		equal(t, err, nil)
+		equal(t, len(layouts), 2)
+		equal(t, layouts[0].String(), "nginx")
+		equal(t, layouts[1].String(), "app")
+
+		got, err := layouts[1].Parse("2019-01-25 21:51:38,272", time.UTC)
+		equal(t, err, nil)
+		equal(t, got, time.Date(2019, 1, 25, 21, 51, 38, 272000000, time.UTC))
+	})
+
+	t.Run("missing layout", func(t *testing.T) {
+		_, err := LoadLayouts(strings.NewReader("nginx"))
+		equalError(t, err, errors.New("line 1: expected 'name: layout': 'nginx'"))
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := LoadLayouts(strings.NewReader("app: 2006\napp: 06"))
+		equalError(t, err, errors.New("line 2: duplicate layout 'app'"))
+	})
+
+	t.Run("invalid layout", func(t *testing.T) {
+		_, err := LoadLayouts(strings.NewReader("app: '%Q'"))
+		equalError(t, err, errors.New("line 1: layout 'app': invalid strftime format: unknown conversion '%Q'"))
+		equal(t, errors.Is(err, ErrStrftime), true)
+	})
+}
+
+func TestParser(t *testing.T) {
+	nginx, err := ParseLayout("02/Jan/2006:15:04:05 -0700")
+	equal(t, err, nil)
+	nginx.Name = "nginx"
+
+	german, err := ParseLayout("%d.%m.%Y")
+	equal(t, err, nil)
+
+	parser := Parser{Layouts: []Layout{nginx, german}}
+
+	testCases := []struct {
+		description    string
+		input          string
+		expected       time.Time
+		expectedLayout string
+		expectedErr    error
+	}{
+		{
+			description:    "named layout",
+			input:          "25/Jan/2019:21:51:38 +0000",
+			expected:       time.Date(2019, 1, 25, 21, 51, 38, 0, time.UTC),
+			expectedLayout: "nginx",
+		},
+		{
+			description:    "second layout",
+			input:          "25.01.2019",
+			expected:       time.Date(2019, 1, 25, 0, 0, 0, 0, time.UTC),
+			expectedLayout: "%d.%m.%Y",
+		},
+		{
+			description:    "built-in layout",
+			input:          "2019-01-25T21:51:38Z",
+			expected:       time.Date(2019, 1, 25, 21, 51, 38, 0, time.UTC),
+			expectedLayout: time.RFC3339,
+		},
+		{
+			description: "no match",
+			input:       "25 January",
+			expectedErr: errors.New("failed to convert string to time: '25 January' looks like RFC822 but expected ' ' at column 7"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, layout, err := parser.Parse(tt.input, time.UTC)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got.Equal(tt.expected), true)
+			equal(t, layout, tt.expectedLayout)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Interval is a half-open time range [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ErrInvalidStep is returned when the step of a sequence doesn't move forward or backward.
+var ErrInvalidStep = errors.New("invalid step")
+
+// Sequence returns the times starting at start (inclusive) in steps of the calculation,
+// e.g. "+1D" for daily or "-1h" for hourly backwards. It stops before reaching end
+// (exclusive, ignored when zero) or after count times (ignored when 0). At least one of them has to be set.
+//
+// The n-th time is calculated from start with n times the amount (e.g. "+3M" for the third month),
+// so month ends don't drift (January 31, February 29, March 31 instead of March 29).
+// The Warn callback of the calculator is only called for the step between two times which crosses a DST transition.
+func Sequence(calculator Calculator, start time.Time, step Calculation, end time.Time, count int) ([]time.Time, error) {
+	return sequence(calculator, start, step, end, count, false)
+}
+
+// Intervals returns up to count half-open intervals between the times of the sequence (see Sequence).
+// The end of the last interval is limited to end (when not zero).
+func Intervals(calculator Calculator, start time.Time, step Calculation, end time.Time, count int) ([]Interval, error) {
+	// one more boundary than intervals is needed
+	if count > 0 {
+		count++
+	}
+
+	boundaries, err := sequence(calculator, start, step, end, count, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var intervals []Interval
+	for i := 0; i+1 < len(boundaries); i++ {
+		intervals = append(intervals, Interval{Start: boundaries[i], End: boundaries[i+1]})
+	}
+	return intervals, nil
+}
+
+// sequence generates the times, when includeEnd is set, end is appended when it was reached.
+func sequence(calculator Calculator, start time.Time, step Calculation, end time.Time, count int, includeEnd bool) ([]time.Time, error) {
+	if step.Operator != Add && step.Operator != Sub {
+		return nil, fmt.Errorf("%w: '%v' (only '+' and '-' are supported)", ErrInvalidStep, step)
+	}
+	if step.Amount <= 0 {
+		return nil, fmt.Errorf("%w: '%v' (amount has to be positive)", ErrInvalidStep, step)
+	}
+	if end.IsZero() && count <= 0 {
+		return nil, errors.New("sequence requires an end or a count")
+	}
+
+	var (
+		times   []time.Time
+		forward = step.Operator == Add
+		warn    = calculator.Warn
+	)
+
+	// all times are calculated from start, only the step crossing a DST transition is reported
+	calculator.Warn = nil
+
+	for i := 0; count <= 0 || i < count; i++ {
+		current := step
+		current.Amount *= i
+
+		t, err := calculator.Calculate(start, current)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			prev := times[len(times)-1]
+			if (forward && !t.After(prev)) || (!forward && !t.Before(prev)) {
+				return nil, fmt.Errorf("%w: '%v' doesn't move the time", ErrInvalidStep, step)
+			}
+			if warn != nil {
+				warnDST(warn, step, prev, t)
+			}
+		}
+
+		if !end.IsZero() && ((forward && !t.Before(end)) || (!forward && !t.After(end))) {
+			if includeEnd && i > 0 {
+				times = append(times, end)
+			}
+			return times, nil
+		}
+
+		times = append(times, t)
+	}
+
+	return times, nil
+}
@@ -0,0 +1,168 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSequence(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description string
+		step        Calculation
+		end         time.Time
+		count       int
+		expected    []time.Time
+		expectedErr error
+	}{
+		{
+			description: "hours/count",
+			step:        Calculation{Operator: Add, Amount: 6, Unit: "h"},
+			count:       3,
+			expected: []time.Time{
+				time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "days/end exclusive",
+			step:        Calculation{Operator: Add, Amount: 1, Unit: "D"},
+			end:         time.Date(2024, 2, 2, 12, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "days/end before count",
+			step:        Calculation{Operator: Add, Amount: 1, Unit: "D"},
+			end:         time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			count:       10,
+			expected: []time.Time{
+				time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "months/no drift at month end",
+			step:        Calculation{Operator: Add, Amount: 1, Unit: "M"},
+			count:       3,
+			expected: []time.Time{
+				time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "backwards",
+			step:        Calculation{Operator: Sub, Amount: 1, Unit: "W"},
+			end:         time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 24, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 17, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "start after end",
+			step:        Calculation{Operator: Add, Amount: 1, Unit: "D"},
+			end:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			description: "truncate step",
+			step:        Calculation{Operator: Truncate, Unit: "D"},
+			count:       2,
+			expectedErr: ErrInvalidStep,
+		},
+		{
+			description: "zero amount",
+			step:        Calculation{Operator: Add, Amount: 0, Unit: "D"},
+			count:       2,
+			expectedErr: ErrInvalidStep,
+		},
+		{
+			description: "unbounded",
+			step:        Calculation{Operator: Add, Amount: 1, Unit: "D"},
+			expectedErr: errors.New("sequence requires an end or a count"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := Sequence(Calculator{}, start, tt.step, tt.end, tt.count)
+			if errors.Is(tt.expectedErr, ErrInvalidStep) {
+				equal(t, errors.Is(err, ErrInvalidStep), true)
+				return
+			}
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
+
+func TestSequenceWarn(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	equal(t, err, nil)
+
+	var warnings []string
+	calculator := Calculator{Warn: func(msg string) { warnings = append(warnings, msg) }}
+
+	// the transition is in the night to 2024-03-31, later times don't cross it again
+	got, err := Sequence(calculator, time.Date(2024, 3, 29, 12, 0, 0, 0, berlin), Calculation{Operator: Add, Amount: 1, Unit: "D"}, time.Time{}, 5)
+	equal(t, err, nil)
+	equal(t, len(got), 5)
+	equal(t, warnings, []string{"+1D crosses a DST transition (CET +0100 to CEST +0200): 23h0m0s elapsed, wall clock changed by 24h0m0s"})
+}
+
+func TestSequenceClamp(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	step := Calculation{Operator: Add, Amount: 1, Unit: "M"}
+
+	got, err := Sequence(Calculator{Overflow: OverflowClamp}, start, step, time.Time{}, 3)
+	equal(t, err, nil)
+	equal(t, got, []time.Time{
+		time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+	})
+}
+
+func TestIntervals(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	step := Calculation{Operator: Add, Amount: 1, Unit: "W"}
+
+	t.Run("count", func(t *testing.T) {
+		got, err := Intervals(Calculator{}, start, step, time.Time{}, 2)
+		equal(t, err, nil)
+		equal(t, got, []Interval{
+			{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+			{Start: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		})
+	})
+
+	t.Run("end clamps last interval", func(t *testing.T) {
+		got, err := Intervals(Calculator{}, start, step, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 0)
+		equal(t, err, nil)
+		equal(t, got, []Interval{
+			{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+			{Start: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		})
+	})
+
+	t.Run("end on boundary", func(t *testing.T) {
+		got, err := Intervals(Calculator{}, start, step, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), 0)
+		equal(t, err, nil)
+		equal(t, got, []Interval{
+			{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package epoch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatRelative is returned by FormatName for the "relative" format.
+// It's no Go layout, use Relative to format times relative to a reference.
+const FormatRelative = "relative"
+
+// relativeUnits are the units of relative times, largest first, with their long and compact names.
+var relativeUnits = []struct {
+	unit, long, compact string
+}{
+	{unit: "Y", long: "year", compact: "y"},
+	{unit: "M", long: "month", compact: "mo"},
+	{unit: "W", long: "week", compact: "w"},
+	{unit: "D", long: "day", compact: "d"},
+	{unit: "h", long: "hour", compact: "h"},
+	{unit: "m", long: "minute", compact: "m"},
+	{unit: "s", long: "second", compact: "s"},
+}
+
+// Relative formats times relative to a reference, e.g. "3 hours ago" or "in 2 days".
+// The zero value formats relative to the current time of the system with the largest unit only, e.g. "in 3 days".
+type Relative struct {
+	// Reference is the time the output is relative to, the time of the clock when zero.
+	Reference time.Time
+	// Clock provides the reference when it's zero. Defaults to the SystemClock.
+	Clock Clock
+	// Precision is the maximum number of units, e.g. 2 for "2 hours 14 minutes ago". Defaults to 1.
+	Precision int
+	// Granularity is the smallest unit: "s" (default), "m", "h", "D", "W", "M" or "Y".
+	Granularity string
+	// Compact uses abbreviated units, e.g. "2h 14m ago".
+	Compact bool
+}
+
+// relativePart is an amount of a unit, e.g. 3 days.
+type relativePart struct {
+	amount int
+	unit   int // index of relativeUnits
+}
+
+// Format returns t relative to the reference, rounded to the last unit, e.g. "2h 14m ago" or "in 3 days".
+// Times closer to the reference than half of the granularity are "now".
+// Days, weeks, months and years are calendar units in the location of the reference (see Calculate).
+func (r Relative) Format(t time.Time) (string, error) {
+	smallest := -1
+	granularity := r.Granularity
+	if granularity == "" {
+		granularity = "s"
+	}
+	for i, u := range relativeUnits {
+		if u.unit == granularity {
+			smallest = i
+		}
+	}
+	if smallest < 0 {
+		return "", fmt.Errorf("%w: '%v' can't be used as granularity", ErrUnknownCalcUnit, r.Granularity)
+	}
+
+	ref := r.Reference
+	if ref.IsZero() {
+		clock := r.Clock
+		if clock == nil {
+			clock = SystemClock
+		}
+		ref = clock.Now()
+	}
+	t = t.In(ref.Location())
+
+	from, to := t, ref
+	future := t.After(ref)
+	if future {
+		from, to = ref, t
+	}
+
+	parts, cursor, last := r.decompose(from, to, smallest)
+
+	// round to the nearest amount of the last unit, which might carry over to larger units
+	if next := addRelative(cursor, relativeUnits[last].unit, 1); to.Sub(cursor) >= next.Sub(to) {
+		parts, _, _ = r.decompose(from, next, smallest)
+	}
+
+	if len(parts) == 0 {
+		return "now", nil
+	}
+
+	words := make([]string, 0, len(parts))
+	for _, part := range parts {
+		u := relativeUnits[part.unit]
+		switch {
+		case r.Compact:
+			words = append(words, strconv.Itoa(part.amount)+u.compact)
+		case part.amount == 1:
+			words = append(words, "1 "+u.long)
+		default:
+			words = append(words, strconv.Itoa(part.amount)+" "+u.long+"s")
+		}
+	}
+
+	if future {
+		return "in " + strings.Join(words, " "), nil
+	}
+	return strings.Join(words, " ") + " ago", nil
+}
+
+// decompose splits the time between from and to into at most Precision consecutive units, starting
+// at the largest unit which fits. It returns the parts, the time after the parts and the last unit.
+func (r Relative) decompose(from, to time.Time, smallest int) ([]relativePart, time.Time, int) {
+	precision := r.Precision
+	if precision < 1 {
+		precision = 1
+	}
+
+	var (
+		parts     []relativePart
+		cursor    = from
+		last      = smallest
+		positions = 0
+	)
+
+	for i := 0; i <= smallest && positions < precision; i++ {
+		n := countRelative(cursor, to, relativeUnits[i].unit)
+		if n == 0 && positions == 0 {
+			continue
+		}
+		if n > 0 {
+			parts = append(parts, relativePart{amount: n, unit: i})
+			cursor = addRelative(cursor, relativeUnits[i].unit, n)
+		}
+		last = i
+		positions++
+	}
+
+	return parts, cursor, last
+}
+
+// relativeShortest are the shortest durations of the calendar units, e.g. a day with a DST transition.
+var relativeShortest = map[string]time.Duration{
+	"D": 23 * time.Hour,
+	"W": 7*24*time.Hour - time.Hour,
+}
+
+// addRelative adds n units to t, days and larger units on the calendar.
+func addRelative(t time.Time, unit string, n int) time.Time {
+	switch unit {
+	case "D":
+		return t.AddDate(0, 0, n)
+	case "W":
+		return t.AddDate(0, 0, 7*n)
+	case "M":
+		return t.AddDate(0, n, 0)
+	case "Y":
+		return t.AddDate(n, 0, 0)
+	}
+	return t.Add(time.Duration(n) * unitDurations[unit])
+}
+
+// countRelative returns how many units fit between from and to.
+func countRelative(from, to time.Time, unit string) int {
+	if d, ok := unitDurations[unit]; ok && unit != "D" && unit != "W" {
+		return int(to.Sub(from) / d)
+	}
+
+	// estimate months and years on the calendar, a time.Duration only holds about 292 years,
+	// and the other units with their shortest duration, then correct on the calendar
+	var n int
+	switch unit {
+	case "M":
+		n = (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	case "Y":
+		n = to.Year() - from.Year()
+	default:
+		n = int(to.Sub(from) / relativeShortest[unit])
+	}
+	for n > 0 && addRelative(from, unit, n).After(to) {
+		n--
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRelativeFormat(t *testing.T) {
+	ref := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description string
+		relative    Relative
+		given       time.Time
+		expected    string
+		expectedErr error
+	}{
+		{description: "past", given: ref.Add(-3 * time.Hour), expected: "3 hours ago"},
+		{description: "future", given: ref.AddDate(0, 0, 3), expected: "in 3 days"},
+		{description: "singular", given: ref.Add(-time.Minute), expected: "1 minute ago"},
+		{description: "now", given: ref.Add(400 * time.Millisecond), expected: "now"},
+		{description: "rounded", given: ref.Add(2*24*time.Hour + 20*time.Hour), expected: "in 3 days"},
+		{description: "rounding carries over", given: ref.Add(-59*time.Minute - 40*time.Second), expected: "1 hour ago"},
+		{description: "precision", relative: Relative{Precision: 2}, given: ref.Add(-2*time.Hour - 14*time.Minute - 20*time.Second), expected: "2 hours 14 minutes ago"},
+		{description: "compact", relative: Relative{Precision: 2, Compact: true}, given: ref.Add(-2*time.Hour - 14*time.Minute - 20*time.Second), expected: "2h 14m ago"},
+		{description: "precision skips zero units", relative: Relative{Precision: 3, Compact: true}, given: ref.Add(26*time.Hour + 5*time.Second), expected: "in 1d 2h"},
+		{description: "granularity", relative: Relative{Precision: 3, Granularity: "m"}, given: ref.Add(-90 * time.Second), expected: "2 minutes ago"},
+		{description: "granularity now", relative: Relative{Granularity: "h"}, given: ref.Add(29 * time.Minute), expected: "now"},
+		{description: "calendar months", relative: Relative{Precision: 3}, given: time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC), expected: "2 months 5 days ago"},
+		{description: "years", relative: Relative{Compact: true}, given: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), expected: "3y ago"},
+		{description: "beyond a duration", relative: Relative{Precision: 2}, given: ref.AddDate(634, 2, 0), expected: "in 634 years 2 months"},
+		{description: "long ago", given: ref.AddDate(-500, 0, 0), expected: "500 years ago"},
+		{description: "weeks", given: ref.AddDate(0, 0, 15), expected: "in 2 weeks"},
+		{description: "unknown granularity", relative: Relative{Granularity: "B"}, given: ref, expectedErr: errors.New("unknown calculation unit: 'B' can't be used as granularity")},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			tt.relative.Reference = ref
+			got, err := tt.relative.Format(tt.given)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
+
+func TestRelativeFormatDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the day of the DST transition only has 23 hours
+	ref := time.Date(2024, 3, 30, 12, 0, 0, 0, berlin)
+	got, err := Relative{Reference: ref}.Format(time.Date(2024, 3, 31, 12, 0, 0, 0, berlin))
+	equal(t, err, nil)
+	equal(t, got, "in 1 day")
+}
+
+func TestRelativeFormatClock(t *testing.T) {
+	clock := FixedClock(time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC))
+
+	got, err := Relative{Clock: clock}.Format(time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC))
+	equal(t, err, nil)
+	equal(t, got, "3 hours ago")
+
+	// the reference has priority
+	got, err = Relative{Reference: time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC), Clock: clock}.Format(time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC))
+	equal(t, err, nil)
+	equal(t, got, "1 hour ago")
+}
@@ -0,0 +1,716 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ of a recurrence rule.
+type Frequency uint
+
+const (
+	// Secondly repeats every second.
+	Secondly Frequency = iota
+	// Minutely repeats every minute.
+	Minutely
+	// Hourly repeats every hour.
+	Hourly
+	// Daily repeats every day.
+	Daily
+	// Weekly repeats every week.
+	Weekly
+	// Monthly repeats every month.
+	Monthly
+	// Yearly repeats every year.
+	Yearly
+)
+
+var frequencyNames = []string{"SECONDLY", "MINUTELY", "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+// emptyPeriods limits the number of consecutive periods without any occurrence,
+// otherwise, rules which never match (e.g. February 30) would search forever.
+var emptyPeriods = []int{
+	Secondly: 2 * 24 * 60 * 60,
+	Minutely: 366 * 24 * 60,
+	Hourly:   searchYears * 366 * 24,
+	Daily:    searchYears * 366,
+	Weekly:   searchYears * 53,
+	Monthly:  searchYears * 12,
+	Yearly:   searchYears,
+}
+
+// WeekdayNum is an entry of BYDAY, e.g. "-1FR" for the last Friday.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	// N is the n-th occurrence within the month (or year), negative values count from the end, 0 matches every occurrence.
+	N int
+}
+
+// RRule is a recurrence rule as defined in RFC 5545 (iCalendar).
+// BYWEEKNO and BYYEARDAY are not supported.
+type RRule struct {
+	// Start is the DTSTART of the rule. Its location is used to evaluate the rule.
+	Start      time.Time
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByMonth    []int
+	ByMonthDay []int
+	ByDay      []WeekdayNum
+	ByHour     []int
+	ByMinute   []int
+	BySecond   []int
+	BySetPos   []int
+	WeekStart  time.Weekday
+
+	// err ended the last iteration early
+	err error
+}
+
+// ErrParseRRule is returned when a recurrence rule is invalid.
+var ErrParseRRule = errors.New("failed to parse recurrence rule")
+
+var rruleWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRRule parses a recurrence rule such as "FREQ=WEEKLY;BYDAY=MO,WE;BYHOUR=9;BYMINUTE=0;BYSECOND=0".
+// The rule might be prefixed with "RRULE:" and preceded by a DTSTART property (separated by a newline
+// or whitespace), e.g. "DTSTART;TZID=Europe/Berlin:20240101T090000 RRULE:FREQ=DAILY".
+// Without DTSTART, start is used. The start is only an occurrence when it matches the rule.
+func ParseRRule(s string, start time.Time) (*RRule, error) {
+	rule := &RRule{Start: start, Interval: 1, WeekStart: time.Monday}
+
+	var ruleParts []string
+
+	for _, line := range strings.Fields(s) {
+		upper := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(upper, "DTSTART"):
+			var err error
+			rule.Start, err = parseICalTime(line[len("DTSTART"):], start.Location())
+			if err != nil {
+				return nil, fmt.Errorf("%w: DTSTART: %v", ErrParseRRule, err)
+			}
+		case strings.HasPrefix(upper, "RRULE:"):
+			ruleParts = append(ruleParts, line[len("RRULE:"):])
+		default:
+			ruleParts = append(ruleParts, line)
+		}
+	}
+
+	if len(ruleParts) != 1 {
+		return nil, fmt.Errorf("%w: expected exactly one rule, got %v", ErrParseRRule, len(ruleParts))
+	}
+
+	hasFreq := false
+
+	for _, part := range strings.Split(ruleParts[0], ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: invalid part '%v'", ErrParseRRule, part)
+		}
+
+		var err error
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			index := slices.Index(frequencyNames, strings.ToUpper(value))
+			if index < 0 {
+				return nil, fmt.Errorf("%w: unknown frequency '%v'", ErrParseRRule, value)
+			}
+			rule.Freq, hasFreq = Frequency(index), true
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+			if err == nil && rule.Interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(value)
+			if err == nil && rule.Count < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "UNTIL":
+			rule.Until, err = parseICalTime(":"+value, rule.Start.Location())
+		case "BYMONTH":
+			rule.ByMonth, err = parseRRuleInts(value, 1, 12, false)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseRRuleInts(value, 1, 31, true)
+		case "BYHOUR":
+			rule.ByHour, err = parseRRuleInts(value, 0, 23, false)
+		case "BYMINUTE":
+			rule.ByMinute, err = parseRRuleInts(value, 0, 59, false)
+		case "BYSECOND":
+			rule.BySecond, err = parseRRuleInts(value, 0, 59, false)
+		case "BYSETPOS":
+			rule.BySetPos, err = parseRRuleInts(value, 1, 366, true)
+		case "BYDAY":
+			rule.ByDay, err = parseRRuleWeekdays(value)
+		case "WKST":
+			index := slices.Index(rruleWeekdays, strings.ToUpper(value))
+			if index < 0 {
+				err = fmt.Errorf("unknown weekday '%v'", value)
+			}
+			rule.WeekStart = time.Weekday(index)
+		default:
+			return nil, fmt.Errorf("%w: unsupported part '%v'", ErrParseRRule, name)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v: %v", ErrParseRRule, name, err)
+		}
+	}
+
+	if !hasFreq {
+		return nil, fmt.Errorf("%w: missing FREQ", ErrParseRRule)
+	}
+
+	return rule, nil
+}
+
+// parseICalTime parses the parameters and value of an iCalendar date-time property,
+// e.g. ";TZID=Europe/Berlin:20240101T090000", ":20240101T090000Z" or ";VALUE=DATE:20240101".
+// Floating times without TZID use the given location.
+func parseICalTime(s string, loc *time.Location) (time.Time, error) {
+	params, value, found := strings.Cut(s, ":")
+	if !found {
+		return time.Time{}, fmt.Errorf("missing value in '%v'", s)
+	}
+
+	for _, param := range strings.Split(params, ";") {
+		if name, tz, found := strings.Cut(param, "="); found && strings.EqualFold(name, "TZID") {
+			var err error
+			loc, err = time.LoadLocation(tz)
+			if err != nil {
+				return time.Time{}, err
+			}
+		}
+	}
+
+	if utc, found := strings.CutSuffix(value, "Z"); found {
+		value, loc = utc, time.UTC
+	}
+
+	for _, layout := range []string{"20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date-time '%v'", value)
+}
+
+func parseRRuleInts(s string, min, max int, negative bool) ([]int, error) {
+	var values []int
+
+	for _, part := range strings.Split(s, ",") {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%v'", part)
+		}
+
+		abs := value
+		if negative && value < 0 {
+			abs = -value
+		}
+		if abs < min || abs > max {
+			return nil, fmt.Errorf("value %v out of range", value)
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func parseRRuleWeekdays(s string) ([]WeekdayNum, error) {
+	var weekdays []WeekdayNum
+
+	for _, part := range strings.Split(strings.ToUpper(s), ",") {
+		if len(part) < 2 {
+			return nil, fmt.Errorf("invalid weekday '%v'", part)
+		}
+
+		index := slices.Index(rruleWeekdays, part[len(part)-2:])
+		if index < 0 {
+			return nil, fmt.Errorf("invalid weekday '%v'", part)
+		}
+
+		weekday := WeekdayNum{Weekday: time.Weekday(index)}
+
+		if ordinal := part[:len(part)-2]; ordinal != "" {
+			n, err := strconv.Atoi(ordinal)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid weekday '%v'", part)
+			}
+			weekday.N = n
+		}
+
+		weekdays = append(weekdays, weekday)
+	}
+
+	return weekdays, nil
+}
+
+// All returns all occurrences of the rule in ascending order, starting at the start of the rule.
+// Without COUNT or UNTIL, the sequence is infinite.
+func (r *RRule) All() iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		count := 0
+		for occurrence := range r.ascending(0) {
+			if r.Count > 0 && count >= r.Count {
+				return
+			}
+			count++
+			if !yield(occurrence) {
+				return
+			}
+		}
+	}
+}
+
+// AllAfter returns the occurrences after t in ascending order.
+// Without COUNT, it starts at the period of t instead of the start of the rule.
+func (r *RRule) AllAfter(t time.Time) iter.Seq[time.Time] {
+	occurrences := r.All()
+	if r.Count == 0 {
+		// the previous period might still have occurrences after t, e.g. for BYSETPOS
+		occurrences = r.ascending(max(r.period(t)-1, 0))
+	}
+
+	return func(yield func(time.Time) bool) {
+		for occurrence := range occurrences {
+			if occurrence.After(t) && !yield(occurrence) {
+				return
+			}
+		}
+	}
+}
+
+// AllBefore returns the occurrences before t in descending order.
+// Without COUNT, it starts at the period of t (or UNTIL) instead of the start of the rule.
+func (r *RRule) AllBefore(t time.Time) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		if r.Count > 0 {
+			// COUNT is counted from the start
+			var occurrences []time.Time
+			for occurrence := range r.All() {
+				if !occurrence.Before(t) {
+					break
+				}
+				occurrences = append(occurrences, occurrence)
+			}
+			for _, occurrence := range slices.Backward(occurrences) {
+				if !yield(occurrence) {
+					return
+				}
+			}
+			return
+		}
+
+		end := t
+		if !r.Until.IsZero() && r.Until.Before(end) {
+			end = r.Until
+		}
+
+		r.err = nil
+		empty := 0
+		last := t
+		for period := r.period(end) + 1; period >= 0; period-- {
+			candidates, err := r.expand(period)
+			if err != nil {
+				r.err = err
+				return
+			}
+
+			if len(candidates) == 0 {
+				if skipped, ok := r.skipDays(period, true); ok {
+					period = skipped + 1
+					continue
+				}
+				empty++
+				if empty > emptyPeriods[r.Freq] {
+					return
+				}
+				continue
+			}
+			empty = 0
+
+			for _, candidate := range slices.Backward(candidates) {
+				// periods might overlap during DST transitions
+				if !candidate.Before(last) || candidate.Before(r.Start) || (!r.Until.IsZero() && candidate.After(r.Until)) {
+					continue
+				}
+				last = candidate
+				if !yield(candidate) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ascending returns the occurrences from the given period on, without applying COUNT.
+func (r *RRule) ascending(from int) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		r.err = nil
+		empty := 0
+		var last time.Time
+
+		for period := from; ; period++ {
+			candidates, err := r.expand(period)
+			if err != nil {
+				r.err = err
+				return
+			}
+
+			if len(candidates) == 0 {
+				if skipped, ok := r.skipDays(period, false); ok {
+					period = skipped - 1
+					continue
+				}
+				empty++
+				if empty > emptyPeriods[r.Freq] {
+					return
+				}
+				continue
+			}
+			empty = 0
+
+			for _, candidate := range candidates {
+				// periods might overlap during DST transitions
+				if candidate.Before(r.Start) || (!last.IsZero() && !candidate.After(last)) {
+					continue
+				}
+				last = candidate
+				if !r.Until.IsZero() && candidate.After(r.Until) {
+					return
+				}
+				if !yield(candidate) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Err returns the error which ended the last iteration early,
+// e.g. ErrOutOfRange when a sub-daily rule exceeds the range of a time.Duration.
+func (r *RRule) Err() error {
+	return r.err
+}
+
+// Next returns the first occurrence after t.
+func (r *RRule) Next(t time.Time) (time.Time, bool) {
+	for occurrence := range r.AllAfter(t) {
+		return occurrence, true
+	}
+	return time.Time{}, false
+}
+
+// Prev returns the last occurrence before t.
+func (r *RRule) Prev(t time.Time) (time.Time, bool) {
+	for occurrence := range r.AllBefore(t) {
+		return occurrence, true
+	}
+	return time.Time{}, false
+}
+
+// frequencyUnits are the lengths of the sub-daily frequencies.
+var frequencyUnits = map[Frequency]time.Duration{Hourly: time.Hour, Minutely: time.Minute, Secondly: time.Second}
+
+// period returns the index of the period containing t (see expand), 0 before the start of the rule.
+func (r *RRule) period(t time.Time) int {
+	start := r.Start
+	t = t.In(start.Location())
+	if !t.After(start) {
+		return 0
+	}
+
+	var units int
+	switch r.Freq {
+	case Yearly:
+		units = t.Year() - start.Year()
+	case Monthly:
+		units = (t.Year()-start.Year())*12 + int(t.Month()-start.Month())
+	case Weekly:
+		units = (daysBetween(start, t) + (int(start.Weekday())-int(r.WeekStart)+7)%7) / 7
+	case Daily:
+		units = daysBetween(start, t)
+	default:
+		// a time.Duration only holds about 292 years
+		units = int((t.Unix() - start.Unix()) / int64(frequencyUnits[r.Freq]/time.Second))
+	}
+	return units / r.Interval
+}
+
+// subDaily returns the time of the period of a sub-daily frequency.
+func (r *RRule) subDaily(period int) (time.Time, error) {
+	step := period * r.Interval
+	if period != 0 && step/period != r.Interval {
+		return time.Time{}, fmt.Errorf("period %v of %v %w", period, frequencyNames[r.Freq], ErrOutOfRange)
+	}
+	duration, ok := scaleDuration(step, frequencyUnits[r.Freq])
+	if !ok {
+		return time.Time{}, fmt.Errorf("period %v of %v %w", period, frequencyNames[r.Freq], ErrOutOfRange)
+	}
+	return r.Start.Add(duration), nil
+}
+
+// skipDays returns the period of the next (or previous when backward) date matching BYMONTH, BYMONTHDAY
+// and BYDAY, when the date of the sub-daily period doesn't match. Otherwise, rules like FREQ=SECONDLY;BYMONTH=2
+// would iterate every second of the months in between.
+func (r *RRule) skipDays(period int, backward bool) (int, bool) {
+	if r.Freq >= Daily {
+		return 0, false
+	}
+	t, err := r.subDaily(period)
+	if err != nil {
+		return 0, false
+	}
+
+	loc := r.Start.Location()
+	t = t.In(loc)
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if r.matchesDate(date) {
+		return 0, false
+	}
+
+	step := 1
+	if backward {
+		step = -1
+	}
+	for range searchYears * 366 {
+		date = date.AddDate(0, 0, step)
+		if !r.matchesDate(date) {
+			continue
+		}
+		if backward {
+			// the last period of the date
+			next := time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, loc)
+			return min(r.period(next), period-1), true
+		}
+		return max(r.period(time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)), period+1), true
+	}
+	return 0, false
+}
+
+// daysBetween returns the number of calendar days from the date of a to the date of b.
+func daysBetween(a, b time.Time) int {
+	dateA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dateB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int((dateB.Unix() - dateA.Unix()) / (24 * 60 * 60))
+}
+
+// expand returns the sorted occurrences within the n-th period (e.g. the n-th month for FREQ=MONTHLY).
+func (r *RRule) expand(period int) ([]time.Time, error) {
+	var (
+		start = r.Start
+		loc   = start.Location()
+		step  = period * r.Interval
+		days  []time.Time // dates in UTC
+	)
+
+	year, month, day := start.Date()
+
+	switch r.Freq {
+	case Yearly:
+		year += step
+		months := r.ByMonth
+		if len(months) == 0 {
+			if len(r.ByDay) > 0 && len(r.ByMonthDay) == 0 {
+				// weekdays within the whole year, e.g. the 20th Monday of the year
+				days = r.matchingDays(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC), day)
+				break
+			}
+			if len(r.ByMonthDay) > 0 {
+				months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			} else {
+				months = []int{int(month)}
+			}
+		}
+		for _, m := range months {
+			first := time.Date(year, time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+			days = append(days, r.matchingDays(first, first.AddDate(0, 1, 0), day)...)
+		}
+	case Monthly:
+		first := time.Date(year, month+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if r.matchesMonth(first) {
+			days = r.matchingDays(first, first.AddDate(0, 1, 0), day)
+		}
+	case Weekly:
+		weekStart := time.Date(year, month, day-(int(start.Weekday())-int(r.WeekStart)+7)%7+7*step, 0, 0, 0, 0, time.UTC)
+		for i := range 7 {
+			date := weekStart.AddDate(0, 0, i)
+			if !r.matchesMonth(date) {
+				continue
+			}
+			if len(r.ByDay) == 0 && date.Weekday() != start.Weekday() {
+				continue
+			}
+			if len(r.ByDay) > 0 && !slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool { return w.Weekday == date.Weekday() }) {
+				continue
+			}
+			days = append(days, date)
+		}
+	case Daily:
+		date := time.Date(year, month, day+step, 0, 0, 0, 0, time.UTC)
+		if r.matchesDate(date) {
+			days = append(days, date)
+		}
+	default:
+		// sub-daily frequencies: the period is a single point in time, the BY parts limit it
+		t, err := r.subDaily(period)
+		if err != nil {
+			return nil, err
+		}
+		t = t.In(loc)
+
+		date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		if !r.matchesDate(date) ||
+			(len(r.ByHour) > 0 && !slices.Contains(r.ByHour, t.Hour())) {
+			return nil, nil
+		}
+
+		minutes, seconds := []int{t.Minute()}, []int{t.Second()}
+		if r.Freq == Hourly && len(r.ByMinute) > 0 {
+			minutes = r.ByMinute
+		} else if len(r.ByMinute) > 0 && !slices.Contains(r.ByMinute, t.Minute()) {
+			return nil, nil
+		}
+		if r.Freq != Secondly && len(r.BySecond) > 0 {
+			seconds = r.BySecond
+		} else if len(r.BySecond) > 0 && !slices.Contains(r.BySecond, t.Second()) {
+			return nil, nil
+		}
+
+		// offsets from the start of the hour keep the offset of t, e.g. 02:30 CEST and CET when DST ends
+		hour := t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+
+		var candidates []time.Time
+		for _, min := range minutes {
+			for _, sec := range seconds {
+				candidates = append(candidates, hour.Add(time.Duration(min)*time.Minute+time.Duration(sec)*time.Second))
+			}
+		}
+		return r.limit(candidates), nil
+	}
+
+	hours := orDefault(r.ByHour, start.Hour())
+	minutes := orDefault(r.ByMinute, start.Minute())
+	seconds := orDefault(r.BySecond, start.Second())
+
+	var candidates []time.Time
+	for _, date := range days {
+		for _, hour := range hours {
+			for _, min := range minutes {
+				for _, sec := range seconds {
+					candidate := earliest(time.Date(date.Year(), date.Month(), date.Day(), hour, min, sec, start.Nanosecond(), loc))
+					// skip times which don't exist because of a DST transition
+					if candidate.Hour() != hour || candidate.Day() != date.Day() {
+						continue
+					}
+					candidates = append(candidates, candidate)
+				}
+			}
+		}
+	}
+
+	return r.limit(candidates), nil
+}
+
+// limit sorts the candidates, removes duplicates and applies BYSETPOS.
+func (r *RRule) limit(candidates []time.Time) []time.Time {
+	slices.SortFunc(candidates, func(a, b time.Time) int { return a.Compare(b) })
+	candidates = slices.CompactFunc(candidates, func(a, b time.Time) bool { return a.Equal(b) })
+
+	if len(r.BySetPos) == 0 {
+		return candidates
+	}
+
+	var selected []time.Time
+	for _, pos := range r.BySetPos {
+		index := pos - 1
+		if pos < 0 {
+			index = len(candidates) + pos
+		}
+		if index >= 0 && index < len(candidates) {
+			selected = append(selected, candidates[index])
+		}
+	}
+	slices.SortFunc(selected, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(selected, func(a, b time.Time) bool { return a.Equal(b) })
+}
+
+func (r *RRule) matchesMonth(date time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, int(date.Month()))
+}
+
+// matchesDate checks BYMONTH, BYMONTHDAY and BYDAY (without ordinals) for daily and shorter frequencies.
+func (r *RRule) matchesDate(date time.Time) bool {
+	if !r.matchesMonth(date) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !matchesMonthDay(r.ByMonthDay, date) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool { return w.Weekday == date.Weekday() }) {
+		return false
+	}
+	return true
+}
+
+// matchingDays returns the days in [from, to) matching BYMONTHDAY and BYDAY,
+// where the ordinals of BYDAY are relative to the range. Without both, the day of the start is used.
+func (r *RRule) matchingDays(from, to time.Time, startDay int) []time.Time {
+	var days []time.Time
+
+	for date := from; date.Before(to); date = date.AddDate(0, 0, 1) {
+		if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+			if date.Day() == startDay {
+				days = append(days, date)
+			}
+			continue
+		}
+
+		if len(r.ByMonthDay) > 0 && !matchesMonthDay(r.ByMonthDay, date) {
+			continue
+		}
+
+		if len(r.ByDay) > 0 {
+			nth := int(date.Sub(from).Hours()/24)/7 + 1
+			nthFromEnd := -(int(to.Sub(date).Hours()/24)-1)/7 - 1
+
+			if !slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool {
+				return w.Weekday == date.Weekday() && (w.N == 0 || w.N == nth || w.N == nthFromEnd)
+			}) {
+				continue
+			}
+		}
+
+		days = append(days, date)
+	}
+
+	return days
+}
+
+func matchesMonthDay(monthDays []int, date time.Time) bool {
+	last := daysIn(date.Year(), date.Month())
+	for _, day := range monthDays {
+		if day == date.Day() || (day < 0 && last+day+1 == date.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(values []int, def int) []int {
+	if len(values) == 0 {
+		return []int{def}
+	}
+	return values
+}
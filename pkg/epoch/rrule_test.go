@@ -0,0 +1,403 @@
+package epoch
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Monday
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, berlin)
+
+	testCases := []struct {
+		description string
+		rule        string
+		expected    []time.Time
+		expectedErr error
+	}{
+		{
+			description: "daily with count",
+			rule:        "FREQ=DAILY;COUNT=3",
+			expected: []time.Time{
+				time.Date(2024, 1, 1, 9, 0, 0, 0, berlin),
+				time.Date(2024, 1, 2, 9, 0, 0, 0, berlin),
+				time.Date(2024, 1, 3, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "weekly with interval",
+			rule:        "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=4",
+			expected: []time.Time{
+				time.Date(2024, 1, 1, 9, 0, 0, 0, berlin),
+				time.Date(2024, 1, 5, 9, 0, 0, 0, berlin),
+				time.Date(2024, 1, 15, 9, 0, 0, 0, berlin),
+				time.Date(2024, 1, 19, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "last friday of the month",
+			rule:        "FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17;BYMINUTE=0;COUNT=3",
+			expected: []time.Time{
+				time.Date(2024, 1, 26, 17, 0, 0, 0, berlin),
+				time.Date(2024, 2, 23, 17, 0, 0, 0, berlin),
+				time.Date(2024, 3, 29, 17, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "last working day of the month",
+			rule:        "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=3",
+			expected: []time.Time{
+				time.Date(2024, 1, 31, 9, 0, 0, 0, berlin),
+				time.Date(2024, 2, 29, 9, 0, 0, 0, berlin),
+				time.Date(2024, 3, 29, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "monthly skips missing days",
+			rule:        "DTSTART;TZID=Europe/Berlin:20240131T090000 RRULE:FREQ=MONTHLY;COUNT=3",
+			expected: []time.Time{
+				time.Date(2024, 1, 31, 9, 0, 0, 0, berlin),
+				time.Date(2024, 3, 31, 9, 0, 0, 0, berlin),
+				time.Date(2024, 5, 31, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "yearly thanksgiving with until",
+			rule:        "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;UNTIL=20251231T000000Z",
+			expected: []time.Time{
+				time.Date(2024, 11, 28, 9, 0, 0, 0, berlin),
+				time.Date(2025, 11, 27, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "utc start",
+			rule:        "DTSTART:20230101T120000Z RRULE:FREQ=DAILY;COUNT=2",
+			expected: []time.Time{
+				time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+				time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "utc until",
+			rule:        "FREQ=DAILY;UNTIL=20240103T083000Z",
+			expected: []time.Time{
+				time.Date(2024, 1, 1, 9, 0, 0, 0, berlin),
+				time.Date(2024, 1, 2, 9, 0, 0, 0, berlin),
+				time.Date(2024, 1, 3, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "yearly by month day",
+			rule:        "FREQ=YEARLY;BYMONTHDAY=-1;BYMONTH=2;COUNT=2",
+			expected: []time.Time{
+				time.Date(2024, 2, 29, 9, 0, 0, 0, berlin),
+				time.Date(2025, 2, 28, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "hourly",
+			rule:        "FREQ=HOURLY;INTERVAL=6;BYMINUTE=0,30;COUNT=3",
+			expected: []time.Time{
+				time.Date(2024, 1, 1, 9, 0, 0, 0, berlin),
+				time.Date(2024, 1, 1, 9, 30, 0, 0, berlin),
+				time.Date(2024, 1, 1, 15, 0, 0, 0, berlin),
+			},
+		},
+		{
+			description: "never",
+			rule:        "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+		},
+		{
+			description: "missing frequency",
+			rule:        "COUNT=3",
+			expectedErr: errors.New("failed to parse recurrence rule: missing FREQ"),
+		},
+		{
+			description: "unsupported part",
+			rule:        "FREQ=YEARLY;BYWEEKNO=20",
+			expectedErr: errors.New("failed to parse recurrence rule: unsupported part 'BYWEEKNO'"),
+		},
+		{
+			description: "invalid weekday",
+			rule:        "FREQ=WEEKLY;BYDAY=XX",
+			expectedErr: errors.New("failed to parse recurrence rule: BYDAY: invalid weekday 'XX'"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule, start)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+
+			got := slices.Collect(rule.All())
+			equal(t, len(got), len(tt.expected))
+			for i := range got {
+				if !got[i].Equal(tt.expected[i]) {
+					t.Fatalf("occurrence %v: got %v, want %v", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRRuleNextPrev(t *testing.T) {
+	rule, err := ParseRRule("FREQ=WEEKLY;BYDAY=TU", time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	next, ok := rule.Next(ref)
+	equal(t, ok, true)
+	equal(t, next, time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC))
+
+	prev, ok := rule.Prev(ref)
+	equal(t, ok, true)
+	equal(t, prev, time.Date(2024, 1, 9, 9, 0, 0, 0, time.UTC))
+
+	_, ok = rule.Prev(time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC))
+	equal(t, ok, false)
+}
+
+func TestRRuleOccurrences(t *testing.T) {
+	ref := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description string
+		rule        string
+		count       int
+		expected    []time.Time
+		expectedErr error
+	}{
+		{
+			description: "minutely since 2000",
+			rule:        "DTSTART:20000101T000000Z RRULE:FREQ=MINUTELY",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2024, 6, 1, 0, 1, 0, 0, time.UTC),
+				time.Date(2024, 6, 1, 0, 2, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "secondly since 2000 backwards",
+			rule:        "DTSTART:20000101T000000Z RRULE:FREQ=SECONDLY;INTERVAL=7",
+			count:       -2,
+			expected: []time.Time{
+				time.Date(2024, 5, 31, 23, 59, 53, 0, time.UTC),
+				time.Date(2024, 5, 31, 23, 59, 46, 0, time.UTC),
+			},
+		},
+		{
+			description: "count",
+			rule:        "DTSTART:20240530T120000Z RRULE:FREQ=DAILY;COUNT=4",
+			count:       3,
+			expected: []time.Time{
+				time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 6, 2, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "until backwards",
+			rule:        "DTSTART:20240101T120000Z RRULE:FREQ=WEEKLY;UNTIL=20240220T000000Z",
+			count:       -1,
+			expected: []time.Time{
+				time.Date(2024, 2, 19, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "secondly in a later month",
+			rule:        "DTSTART:20240301T000000Z RRULE:FREQ=SECONDLY;BYMONTH=2",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 1, 0, 0, 1, 0, time.UTC),
+			},
+		},
+		{
+			description: "secondly in an earlier month backwards",
+			rule:        "DTSTART:20200301T000000Z RRULE:FREQ=SECONDLY;BYMONTH=2;BYMONTHDAY=28",
+			count:       -1,
+			expected: []time.Time{
+				time.Date(2024, 2, 28, 23, 59, 59, 0, time.UTC),
+			},
+		},
+		{
+			description: "interval beyond a duration",
+			rule:        "DTSTART:20240101T000000Z RRULE:FREQ=HOURLY;INTERVAL=1000000000",
+			count:       1,
+			expectedErr: errors.New("period 1 of HOURLY out of range"),
+		},
+		{
+			description: "secondly in a later month",
+			rule:        "DTSTART:20240301T000000Z RRULE:FREQ=SECONDLY;BYMONTH=2",
+			count:       2,
+			expected: []time.Time{
+				time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 1, 0, 0, 1, 0, time.UTC),
+			},
+		},
+		{
+			description: "secondly in an earlier month backwards",
+			rule:        "DTSTART:20200301T000000Z RRULE:FREQ=SECONDLY;BYMONTH=2;BYMONTHDAY=28",
+			count:       -1,
+			expected: []time.Time{
+				time.Date(2024, 2, 28, 23, 59, 59, 0, time.UTC),
+			},
+		},
+		{
+			description: "interval beyond a duration",
+			rule:        "DTSTART:20240101T000000Z RRULE:FREQ=HOURLY;INTERVAL=1000000000",
+			count:       1,
+			expectedErr: errors.New("period 1 of HOURLY out of range"),
+		},
+		{
+			description: "never",
+			rule:        "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			count:       1,
+			expectedErr: errors.New("no occurrences"),
+		},
+		{
+			description: "ended",
+			rule:        "DTSTART:20200101T000000Z RRULE:FREQ=DAILY;COUNT=3",
+			count:       1,
+			expectedErr: errors.New("no occurrences"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Occurrences(rule, ref, tt.count)
+			if err != nil || tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
+
+func TestRRuleDSTFallBack(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// DST ends on 2024-10-27 at 03:00 CEST, the wall clock repeats 02:00 to 03:00
+	start := time.Date(2024, 10, 27, 0, 30, 0, 0, berlin)
+
+	testCases := []struct {
+		rule     string
+		expected []string
+	}{
+		{
+			rule:     "FREQ=HOURLY;COUNT=5",
+			expected: []string{"00:30 CEST", "01:30 CEST", "02:30 CEST", "02:30 CET", "03:30 CET"},
+		},
+		{
+			rule:     "FREQ=HOURLY;BYMINUTE=15,45;COUNT=6",
+			expected: []string{"00:45 CEST", "01:15 CEST", "01:45 CEST", "02:15 CEST", "02:45 CEST", "02:15 CET"},
+		},
+		{
+			rule:     "FREQ=MINUTELY;INTERVAL=30;BYHOUR=2;COUNT=4",
+			expected: []string{"02:00 CEST", "02:30 CEST", "02:00 CET", "02:30 CET"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule, start)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for occurrence := range rule.All() {
+				got = append(got, occurrence.Format("15:04 MST"))
+			}
+			equal(t, got, tt.expected)
+
+			// backwards from the end
+			last, ok := rule.Prev(time.Date(2024, 10, 28, 0, 0, 0, 0, berlin))
+			equal(t, ok, true)
+			equal(t, last.Format("15:04 MST"), tt.expected[len(tt.expected)-1])
+		})
+	}
+}
+
+func TestRRuleAllAfterBefore(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2024, 1, 31, 9, 30, 0, 0, berlin)
+
+	// jumping to the period of t must find the same occurrences as iterating from the start
+	for _, rule := range []string{
+		"FREQ=YEARLY;INTERVAL=2;BYMONTH=3,10;BYDAY=-1SU",
+		"FREQ=MONTHLY;INTERVAL=3",
+		"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU;WKST=SU",
+		"FREQ=DAILY;INTERVAL=5;BYHOUR=2,3",
+		"FREQ=HOURLY;INTERVAL=5;BYMINUTE=0,45",
+		"FREQ=MINUTELY;INTERVAL=97;UNTIL=20240301T000000Z",
+	} {
+		t.Run(rule, func(t *testing.T) {
+			rrule, err := ParseRRule(rule, start)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			all := slices.Collect(func(yield func(time.Time) bool) {
+				for occurrence := range rrule.All() {
+					if occurrence.Year() > 2027 || !yield(occurrence) {
+						return
+					}
+				}
+			})
+
+			for _, ref := range []time.Time{
+				start.Add(-time.Hour),
+				time.Date(2024, 2, 29, 23, 59, 0, 0, berlin),
+				time.Date(2024, 3, 31, 2, 30, 0, 0, time.UTC),
+				time.Date(2025, 10, 26, 2, 45, 0, 0, berlin),
+				all[len(all)/2],
+			} {
+				index, _ := slices.BinarySearchFunc(all, ref, func(a, b time.Time) int { return a.Compare(b) })
+				if index < len(all) && all[index].Equal(ref) {
+					index++
+				}
+				if next, ok := rrule.Next(ref); index < len(all) && (!ok || !next.Equal(all[index])) {
+					t.Fatalf("next after %v: got %v, want %v", ref, next, all[index])
+				}
+
+				index, _ = slices.BinarySearchFunc(all, ref, func(a, b time.Time) int { return a.Compare(b) })
+				prev, ok := rrule.Prev(ref)
+				if index == 0 {
+					equal(t, ok, false)
+				} else if !ok || !prev.Equal(all[index-1]) {
+					t.Fatalf("prev before %v: got %v, want %v", ref, prev, all[index-1])
+				}
+			}
+		})
+	}
+}
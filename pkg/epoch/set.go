@@ -0,0 +1,79 @@
+package epoch
+
+import (
+	"fmt"
+	"time"
+)
+
+// setRanges are the valid values of the components which can be set.
+var setRanges = map[string]struct{ min, max int }{
+	"ns": {0, 999999999},
+	"us": {0, 999999},
+	"ms": {0, 999},
+	"s":  {0, 59},
+	"m":  {0, 59},
+	"h":  {0, 23},
+	"D":  {1, 31},
+	"M":  {1, 12},
+	"Y":  {0, 9999},
+}
+
+// validateSet checks if the value can be assigned to the component of the unit.
+func validateSet(value int, unit string) error {
+	r, ok := setRanges[unit]
+	if !ok {
+		return fmt.Errorf("%w: '%v' can't be set", ErrUnknownCalcUnit, unit)
+	}
+	if value < r.min || value > r.max {
+		return fmt.Errorf("value %v %w for '%v' (%v-%v)", value, ErrOutOfRange, unit, r.min, r.max)
+	}
+	return nil
+}
+
+// SetComponent replaces a single component of t (e.g. the hour for "h") while keeping all other components.
+// "ms", "us" and "ns" replace the fraction of the second with the given precision, e.g. "=5ms" results in ".005".
+//
+// When the resulting date doesn't exist, the day is clamped to the last day of the month,
+// e.g. setting the day to 31 in April results in April 30 and setting the year of
+// February 29 to a non-leap year results in February 28.
+// Times within a skipped DST period are normalized by time.Date.
+// Units without a component ("W", "Q" and "B") return the zero time, Calculator reports them as errors.
+func SetComponent(t time.Time, value int, unit string) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	nsec := t.Nanosecond()
+
+	switch unit {
+	case "ns":
+		nsec = value
+	case "us":
+		nsec = value * 1000
+	case "ms":
+		nsec = value * 1000 * 1000
+	case "s":
+		sec = value
+	case "m":
+		min = value
+	case "h":
+		hour = value
+	case "D":
+		day = value
+	case "M":
+		month = time.Month(value)
+	case "Y":
+		year = value
+	default:
+		return time.Time{}
+	}
+
+	if last := daysIn(year, month); day > last {
+		day = last
+	}
+
+	return time.Date(year, month, day, hour, min, sec, nsec, t.Location())
+}
+
+// daysIn returns the number of days of the month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,140 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSetComponent(t *testing.T) {
+	given := time.Date(2024, 1, 31, 17, 46, 45, 215239000, time.UTC)
+
+	testCases := []struct {
+		description string
+		value       int
+		unit        string
+		expected    time.Time
+	}{
+		{
+			description: "nanoseconds",
+			value:       5,
+			unit:        "ns",
+			expected:    time.Date(2024, 1, 31, 17, 46, 45, 5, time.UTC),
+		},
+		{
+			description: "milliseconds",
+			value:       5,
+			unit:        "ms",
+			expected:    time.Date(2024, 1, 31, 17, 46, 45, 5000000, time.UTC),
+		},
+		{
+			description: "hour",
+			value:       9,
+			unit:        "h",
+			expected:    time.Date(2024, 1, 31, 9, 46, 45, 215239000, time.UTC),
+		},
+		{
+			description: "day",
+			value:       1,
+			unit:        "D",
+			expected:    time.Date(2024, 1, 1, 17, 46, 45, 215239000, time.UTC),
+		},
+		{
+			description: "month/clamped",
+			value:       2,
+			unit:        "M",
+			expected:    time.Date(2024, 2, 29, 17, 46, 45, 215239000, time.UTC),
+		},
+		{
+			description: "year",
+			value:       2030,
+			unit:        "Y",
+			expected:    time.Date(2030, 1, 31, 17, 46, 45, 215239000, time.UTC),
+		},
+		{
+			description: "unknown unit",
+			value:       1,
+			unit:        "W",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			equal(t, SetComponent(given, tt.value, tt.unit), tt.expected)
+		})
+	}
+}
+
+func TestSetComponentClampYear(t *testing.T) {
+	leapDay := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+	equal(t, SetComponent(leapDay, 2025, "Y"), time.Date(2025, 2, 28, 12, 0, 0, 0, time.UTC))
+
+	april := time.Date(2024, 4, 15, 12, 0, 0, 0, time.UTC)
+	equal(t, SetComponent(april, 31, "D"), time.Date(2024, 4, 30, 12, 0, 0, 0, time.UTC))
+}
+
+func TestCalculatorSet(t *testing.T) {
+	given := time.Date(2024, 4, 15, 12, 0, 0, 0, time.UTC)
+
+	got, err := Calculator{}.Calculate(given, Calculation{Operator: Set, Amount: 9, Unit: "h"})
+	equal(t, err, nil)
+	equal(t, got, time.Date(2024, 4, 15, 9, 0, 0, 0, time.UTC))
+
+	for _, unit := range []string{"W", "Q", "B"} {
+		_, err := Calculator{}.Calculate(given, Calculation{Operator: Set, Amount: 1, Unit: unit})
+		equalError(t, err, fmt.Errorf("unknown calculation unit: '%v' can't be set", unit))
+		equal(t, errors.Is(err, ErrUnknownCalcUnit), true)
+	}
+
+	_, err = Calculator{}.Calculate(given, Calculation{Operator: Set, Amount: 24, Unit: "h"})
+	equal(t, errors.Is(err, ErrOutOfRange), true)
+}
+
+func TestParseCalculationsSet(t *testing.T) {
+	testCases := []struct {
+		description string
+		given       string
+		expected    []Calculation
+		expectedErr error
+	}{
+		{
+			description: "time of day",
+			given:       "=09h =0m =0s",
+			expected: []Calculation{
+				{Operator: Set, Amount: 9, Unit: "h"},
+				{Operator: Set, Amount: 0, Unit: "m"},
+				{Operator: Set, Amount: 0, Unit: "s"},
+			},
+		},
+		{
+			description: "out of range",
+			given:       "=24h",
+			expectedErr: errors.New("value 24 out of range for 'h' (0-23)"),
+		},
+		{
+			description: "zero day",
+			given:       "=0D",
+			expectedErr: errors.New("value 0 out of range for 'D' (1-31)"),
+		},
+		{
+			description: "not settable",
+			given:       "=1W",
+			expectedErr: errors.New("unknown calculation unit: 'W' can't be set"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			calculations, err := ParseCalculations(tt.given)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, calculations, tt.expected)
+		})
+	}
+}
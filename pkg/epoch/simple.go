@@ -0,0 +1,220 @@
+package epoch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// simpleToken is a token of the simple format, either with a Go layout element or
+// a custom formatting for tokens Go lacks.
+type simpleToken struct {
+	layout string
+	format func(t time.Time) string
+}
+
+// simpleTokens are the supported tokens of simple formats without braces.
+var simpleTokens = withSimpleFractions(map[string]simpleToken{
+	"YYYY": {layout: "2006"},                                                                     // Long year
+	"YY":   {layout: "06"},                                                                       // Short year
+	"GGGG": {format: func(t time.Time) string { return zeroPad(isoYear(t), 4) }},                 // ISO 8601 week-based year
+	"Q":    {format: func(t time.Time) string { return strconv.Itoa((int(t.Month())-1)/3 + 1) }}, // Quarter
+
+	"MMMM": {layout: "January"},
+	"MMM":  {layout: "Jan"},
+	"MM":   {layout: "01"}, // Month (2-digit)
+	"M":    {layout: "1"},  // Month (1-digit)
+
+	"WW": {format: func(t time.Time) string { return zeroPad(isoWeek(t), 2) }},   // ISO 8601 week (2-digit)
+	"W":  {format: func(t time.Time) string { return strconv.Itoa(isoWeek(t)) }}, // ISO 8601 week (1-digit)
+
+	"DDDD": {layout: "002"},                                                // Day of year (3-digit)
+	"DDD":  {layout: "__2"},                                                // Day of year (space padded)
+	"DD":   {layout: "02"},                                                 // Day of month (2-digit)
+	"D":    {layout: "2"},                                                  // Day of month (1-digit)
+	"Do":   {format: func(t time.Time) string { return ordinal(t.Day()) }}, // Day of month with ordinal suffix (1st)
+
+	"dddd": {layout: "Monday"}, // Day of week
+	"ddd":  {layout: "Mon"},    // Day of week
+
+	"HH": {layout: "15"}, // Hour 24 (2-digit)
+	"hh": {layout: "03"}, // Hour 12 (2-digit)
+	"h":  {layout: "3"},  // Hour 12 (1-digit)
+
+	"A": {layout: "PM"},
+	"a": {layout: "pm"},
+
+	"mm": {layout: "04"}, // Minute (2-digit)
+	"m":  {layout: "4"},  // Minute (1-digit)
+
+	"ss": {layout: "05"}, // Second (2-digit)
+	"s":  {layout: "5"},  // Second (1-digit)
+
+	"X": {format: func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) }},      // Unix timestamp in seconds
+	"x": {format: func(t time.Time) string { return strconv.FormatInt(t.UnixMilli(), 10) }}, // Unix timestamp in milliseconds
+
+	// Timezone / Offset
+	"ZZZ": {layout: "-07:00"},
+	"ZZ":  {layout: "-0700"},
+	"Z":   {layout: "-07"},
+	"z":   {layout: "MST"},
+})
+
+// withSimpleFractions adds the fractional seconds with a fixed ("{FFF}" for ".000")
+// or an arbitrary ("{fff}" for ".999") precision. The dot is part of the output.
+func withSimpleFractions(tokens map[string]simpleToken) map[string]simpleToken {
+	for i := 1; i <= 9; i++ {
+		tokens[strings.Repeat("F", i)] = simpleToken{layout: "." + strings.Repeat("0", i)}
+		tokens[strings.Repeat("f", i)] = simpleToken{layout: "." + strings.Repeat("9", i)}
+	}
+	return tokens
+}
+
+func isoYear(t time.Time) int {
+	year, _ := t.ISOWeek()
+	return year
+}
+
+func isoWeek(t time.Time) int {
+	_, week := t.ISOWeek()
+	return week
+}
+
+// zeroPad returns the value with leading zeros up to the width.
+func zeroPad(value, width int) string {
+	return fmt.Sprintf("%0*d", width, value)
+}
+
+// ordinal returns the number with its English ordinal suffix, e.g. "1st" or "12th".
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		suffix = "th"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// SimpleFormatError is returned when a simple format contains an invalid token.
+type SimpleFormatError struct {
+	Format string
+	// Position is the byte offset of the token in the format.
+	Position int
+	Token    string
+	Reason   string
+}
+
+func (e *SimpleFormatError) Error() string {
+	return fmt.Sprintf("invalid simple format '%v': %v '%v' at position %v", e.Format, e.Reason, e.Token, e.Position)
+}
+
+// simplePart is either literal text or a token.
+type simplePart struct {
+	literal  string
+	token    string
+	position int
+}
+
+// SimpleFormat is a parsed simple format such as "{YYYY}-{MM}-{DD}".
+type SimpleFormat struct {
+	format string
+	parts  []simplePart
+}
+
+// ParseSimpleFormat parses a format with tokens in braces, e.g. "{YYYY}-{MM}-{DD} (week {WW})".
+// Text outside of braces is literal, "{{" and "}}" are literal braces.
+// Unknown tokens and unbalanced braces return a *SimpleFormatError.
+func ParseSimpleFormat(format string) (SimpleFormat, error) {
+	f := SimpleFormat{format: format}
+
+	var literal strings.Builder
+	literalStart := 0
+
+	for i := 0; i < len(format); i++ {
+		switch {
+		case strings.HasPrefix(format[i:], "{{"), strings.HasPrefix(format[i:], "}}"):
+			if literal.Len() == 0 {
+				literalStart = i
+			}
+			literal.WriteByte(format[i])
+			i++
+		case format[i] == '}':
+			return SimpleFormat{}, &SimpleFormatError{Format: format, Position: i, Token: "}", Reason: "unexpected"}
+		case format[i] == '{':
+			end := strings.IndexAny(format[i+1:], "{}")
+			if end < 0 || format[i+1+end] != '}' {
+				return SimpleFormat{}, &SimpleFormatError{Format: format, Position: i, Token: "{", Reason: "unterminated"}
+			}
+			token := format[i+1 : i+1+end]
+			if _, ok := simpleTokens[token]; !ok {
+				return SimpleFormat{}, &SimpleFormatError{Format: format, Position: i, Token: "{" + token + "}", Reason: "unknown token"}
+			}
+			if literal.Len() > 0 {
+				f.parts = append(f.parts, simplePart{literal: literal.String(), position: literalStart})
+				literal.Reset()
+			}
+			f.parts = append(f.parts, simplePart{token: token, position: i})
+			i += end + 1
+		default:
+			if literal.Len() == 0 {
+				literalStart = i
+			}
+			literal.WriteByte(format[i])
+		}
+	}
+
+	if literal.Len() > 0 {
+		f.parts = append(f.parts, simplePart{literal: literal.String(), position: literalStart})
+	}
+	return f, nil
+}
+
+// Format returns the formatted time. Literal text is never interpreted as a Go layout element.
+func (f SimpleFormat) Format(t time.Time) string {
+	return f.formatLocale(t, english)
+}
+
+// formatLocale returns the formatted time with the names of the locale.
+func (f SimpleFormat) formatLocale(t time.Time, locale Locale) string {
+	var sb strings.Builder
+	for _, part := range f.parts {
+		if part.token == "" {
+			sb.WriteString(part.literal)
+			continue
+		}
+		token := simpleTokens[part.token]
+		if token.format != nil {
+			sb.WriteString(token.format(t))
+			continue
+		}
+		sb.WriteString(locale.Format(t, token.layout))
+	}
+	return sb.String()
+}
+
+// Layout returns the equivalent Go layout, e.g. for parsing with time.Parse.
+// It returns ErrUnsupportedToken when a token has no Go equivalent (e.g. "{WW}")
+// or Go would interpret literal text as layout elements (e.g. "Jan").
+func (f SimpleFormat) Layout() (string, error) {
+	parts := make([]layoutPart, 0, len(f.parts))
+	for _, part := range f.parts {
+		if part.token == "" {
+			parts = append(parts, layoutPart{text: part.literal, position: part.position})
+			continue
+		}
+		token := simpleTokens[part.token]
+		if token.layout == "" {
+			return "", fmt.Errorf("%w: simple token '{%v}' at position %v", ErrUnsupportedToken, part.token, part.position)
+		}
+		parts = append(parts, layoutPart{text: token.layout, element: true, position: part.position})
+	}
+	return joinLayout(parts, fmt.Sprintf("simple format '%v'", f.format))
+}
@@ -0,0 +1,84 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSimpleFormat(t *testing.T) {
+	given := time.Date(2022, 1, 2, 7, 6, 5, 4000000, time.FixedZone("CET", 3600))
+
+	testCases := []struct {
+		description string
+		format      string
+		expected    string
+		expectedErr error
+	}{
+		{description: "date", format: "{YYYY}-{MM}-{DD}", expected: "2022-01-02"},
+		{description: "literal go elements", format: "Jan 2006: {D}.{M}.", expected: "Jan 2006: 2.1."},
+		{description: "fractions", format: "{ss}{F} {ss}{FFF} {ss}{fff}", expected: "05.0 05.004 05.004"},
+		{description: "day of year", format: "{DDDD}|{DDD}", expected: "002|  2"},
+		{description: "iso week and quarter", format: "{GGGG}-W{WW} W{W} Q{Q}", expected: "2021-W52 W52 Q1"},
+		{description: "ordinal", format: "{dddd}, {MMMM} {Do}", expected: "Sunday, January 2nd"},
+		{description: "unix timestamps", format: "{X} {x}", expected: "1641103565 1641103565004"},
+		{description: "time zone", format: "{hh}:{mm} {A} {z} {ZZZ}", expected: "07:06 AM CET +01:00"},
+		{description: "escaped braces", format: "{{YYYY}} {YYYY}", expected: "{YYYY} 2022"},
+		{description: "unknown token", format: "{YYYY}-{MMMMM}", expectedErr: &SimpleFormatError{Format: "{YYYY}-{MMMMM}", Position: 7, Token: "{MMMMM}", Reason: "unknown token"}},
+		{description: "unterminated", format: "{YYYY}-{MM", expectedErr: &SimpleFormatError{Format: "{YYYY}-{MM", Position: 7, Token: "{", Reason: "unterminated"}},
+		{description: "nested", format: "{Y{YY}}", expectedErr: &SimpleFormatError{Format: "{Y{YY}}", Position: 0, Token: "{", Reason: "unterminated"}},
+		{description: "unexpected closing brace", format: "{YYYY}}", expectedErr: &SimpleFormatError{Format: "{YYYY}}", Position: 6, Token: "}", Reason: "unexpected"}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			f, err := ParseSimpleFormat(tt.format)
+			if err != nil {
+				equal(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, f.Format(given), tt.expected)
+		})
+	}
+}
+
+func TestSimpleFormatError(t *testing.T) {
+	_, err := ParseSimpleFormat("{YYYY}-{MMMMM}")
+
+	var formatErr *SimpleFormatError
+	equal(t, errors.As(err, &formatErr), true)
+	equal(t, err.Error(), "invalid simple format '{YYYY}-{MMMMM}': unknown token '{MMMMM}' at position 7")
+}
+
+func TestSimpleFormatLayout(t *testing.T) {
+	testCases := []struct {
+		description string
+		format      string
+		expected    string
+		expectedErr error
+	}{
+		{description: "date time", format: "{YYYY}-{MM}-{DD} {HH}:{mm}:{ss}{FFF} {ZZZ}", expected: "2006-01-02 15:04:05.000 -07:00"},
+		{description: "no go equivalent", format: "{YYYY}-W{WW}", expectedErr: errors.New("token not supported by Go layouts: simple token '{WW}' at position 8")},
+		{description: "literal go elements", format: "Jan {D}", expectedErr: errors.New("token not supported by Go layouts: literal text 'Jan ' at position 0 contains Go layout elements")},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			f, err := ParseSimpleFormat(tt.format)
+			equal(t, err, nil)
+
+			layout, err := f.Layout()
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, layout, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,702 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrStrftime is returned when a strftime format is invalid.
+var ErrStrftime = errors.New("invalid strftime format")
+
+// strftimeToken is either a literal text or a single conversion such as "%-d".
+type strftimeToken struct {
+	literal string
+	verb    byte
+	flag    byte // '-' (no padding), '_' (space padding), '0' (zero padding) or '^' (upper case)
+	width   int
+	colons  int // only for %z, e.g. 1 for "%:z"
+	// optional is only used for fractional seconds of Go layouts, which might be missing (e.g. ".999")
+	optional bool
+}
+
+// strftimeComposites are conversions which are shortcuts for other conversions.
+var strftimeComposites = map[byte]string{
+	'c': "%a %b %e %H:%M:%S %Y",
+	'D': "%m/%d/%y",
+	'F': "%Y-%m-%d",
+	'r': "%I:%M:%S %p",
+	'R': "%H:%M",
+	'T': "%H:%M:%S",
+	'x': "%m/%d/%y",
+	'X': "%H:%M:%S",
+}
+
+// strftimeNumbers are the numeric conversions with their default width and padding.
+var strftimeNumbers = map[byte]struct {
+	width int
+	pad   byte
+}{
+	'C': {2, '0'},
+	'd': {2, '0'},
+	'e': {2, ' '},
+	'g': {2, '0'},
+	'G': {4, '0'},
+	'H': {2, '0'},
+	'I': {2, '0'},
+	'j': {3, '0'},
+	'k': {2, ' '},
+	'l': {2, ' '},
+	'm': {2, '0'},
+	'M': {2, '0'},
+	's': {1, '0'},
+	'S': {2, '0'},
+	'u': {1, '0'},
+	'U': {2, '0'},
+	'V': {2, '0'},
+	'w': {1, '0'},
+	'W': {2, '0'},
+	'y': {2, '0'},
+	'Y': {4, '0'},
+}
+
+// tokenizeStrftime splits the format into literals and conversions, composite conversions are expanded.
+func tokenizeStrftime(format string) ([]strftimeToken, error) {
+	var (
+		tokens  []strftimeToken
+		literal strings.Builder
+	)
+
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			tokens = append(tokens, strftimeToken{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			literal.WriteByte(format[i])
+			continue
+		}
+
+		token := strftimeToken{}
+		i++
+
+		if i < len(format) && strings.IndexByte("-_0^", format[i]) >= 0 {
+			token.flag = format[i]
+			i++
+		}
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			token.width = token.width*10 + int(format[i]-'0')
+			i++
+		}
+		for i < len(format) && format[i] == ':' {
+			token.colons++
+			i++
+		}
+		if i >= len(format) {
+			return nil, fmt.Errorf("%w: '%v' ends with an incomplete conversion", ErrStrftime, format)
+		}
+
+		token.verb = format[i]
+
+		switch {
+		case token.verb == '%':
+			literal.WriteByte('%')
+			continue
+		case token.verb == 'n':
+			literal.WriteByte('\n')
+			continue
+		case token.verb == 't':
+			literal.WriteByte('\t')
+			continue
+		case token.colons > 0 && (token.verb != 'z' || token.colons > 2):
+			return nil, fmt.Errorf("%w: unknown conversion '%%%v%c'", ErrStrftime, strings.Repeat(":", token.colons), token.verb)
+		}
+
+		if composite, ok := strftimeComposites[token.verb]; ok {
+			flushLiteral()
+			expanded, err := tokenizeStrftime(composite)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, expanded...)
+			continue
+		}
+
+		if _, ok := strftimeNumbers[token.verb]; !ok && strings.IndexByte("aAbBhNpPzZ", token.verb) < 0 {
+			return nil, fmt.Errorf("%w: unknown conversion '%%%c'", ErrStrftime, token.verb)
+		}
+
+		flushLiteral()
+		tokens = append(tokens, token)
+	}
+
+	flushLiteral()
+	return tokens, nil
+}
+
+// Strftime formats t according to the strftime format, e.g. "%Y-%m-%dT%H:%M:%S%z".
+//
+// Besides the POSIX conversions, the GNU extensions %s (Unix timestamp), %N (nanoseconds, "%3N" for milliseconds),
+// %k and %l (space padded hours), %P (lower case am/pm), %:z and %::z (offsets with colons)
+// and the flags "-" (no padding), "_" (space padding), "0" (zero padding) and "^" (upper case) are supported.
+// Conversions depending on the locale use the C locale, e.g. %c is "%a %b %e %H:%M:%S %Y".
+func Strftime(t time.Time, format string) (string, error) {
+	return english.Strftime(t, format)
+}
+
+func (l Locale) formatStrftimeToken(t time.Time, token strftimeToken) string {
+	var s string
+
+	switch token.verb {
+	case 'a':
+		s = l.ShortWeekdays[t.Weekday()]
+	case 'A':
+		s = l.Weekdays[t.Weekday()]
+	case 'b', 'h':
+		s = l.ShortMonths[t.Month()-1]
+	case 'B':
+		s = l.Months[t.Month()-1]
+	case 'p':
+		s = l.meridiem(t)
+	case 'P':
+		s = strings.ToLower(l.meridiem(t))
+	case 'Z':
+		s, _ = t.Zone()
+	case 'z':
+		s = formatOffset(t, token.colons)
+	case 'N':
+		s = fmt.Sprintf("%09d", t.Nanosecond())
+		if token.width > 0 && token.width < len(s) {
+			s = s[:token.width]
+		}
+		return s
+	default:
+		number := strftimeNumbers[token.verb]
+		return padNumber(strftimeNumber(t, token.verb), token, number.width, number.pad)
+	}
+
+	if token.flag == '^' {
+		s = strings.ToUpper(s)
+	}
+	if len(s) < token.width {
+		pad := " "
+		if token.flag == '0' {
+			pad = "0"
+		}
+		s = strings.Repeat(pad, token.width-len(s)) + s
+	}
+	return s
+}
+
+// strftimeNumber returns the value of a numeric conversion.
+func strftimeNumber(t time.Time, verb byte) int {
+	isoYear, isoWeek := t.ISOWeek()
+
+	switch verb {
+	case 'C':
+		return t.Year() / 100
+	case 'd', 'e':
+		return t.Day()
+	case 'g':
+		return isoYear % 100
+	case 'G':
+		return isoYear
+	case 'H', 'k':
+		return t.Hour()
+	case 'I', 'l':
+		if hour := t.Hour() % 12; hour != 0 {
+			return hour
+		}
+		return 12
+	case 'j':
+		return t.YearDay()
+	case 'm':
+		return int(t.Month())
+	case 'M':
+		return t.Minute()
+	case 's':
+		return int(t.Unix())
+	case 'S':
+		return t.Second()
+	case 'u':
+		if t.Weekday() == time.Sunday {
+			return 7
+		}
+		return int(t.Weekday())
+	case 'U':
+		// weeks starting on Sunday, days before the first Sunday are in week 0
+		return (t.YearDay() + 6 - int(t.Weekday())) / 7
+	case 'V':
+		return isoWeek
+	case 'w':
+		return int(t.Weekday())
+	case 'W':
+		// weeks starting on Monday, days before the first Monday are in week 0
+		return (t.YearDay() + 6 - (int(t.Weekday())+6)%7) / 7
+	case 'y':
+		return t.Year() % 100
+	case 'Y':
+		return t.Year()
+	}
+	return 0
+}
+
+// padNumber pads the value to the width of the token or the default width.
+func padNumber(value int, token strftimeToken, width int, pad byte) string {
+	if token.width > 0 {
+		width = token.width
+	}
+	switch token.flag {
+	case '-':
+		width = 0
+	case '_':
+		pad = ' '
+	case '0':
+		pad = '0'
+	}
+
+	s := strconv.Itoa(value)
+	sign := ""
+	if value < 0 {
+		sign, s = "-", s[1:]
+	}
+	if len(s) < width-len(sign) {
+		s = strings.Repeat(string(pad), width-len(sign)-len(s)) + s
+	}
+	return sign + s
+}
+
+// formatOffset formats the UTC offset as "+hhmm", "+hh:mm" (1 colon) or "+hh:mm:ss" (2 colons).
+func formatOffset(t time.Time, colons int) string {
+	_, offset := t.Zone()
+
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	hours, minutes, seconds := offset/3600, offset/60%60, offset%60
+
+	switch colons {
+	case 1:
+		return fmt.Sprintf("%c%02d:%02d", sign, hours, minutes)
+	case 2:
+		return fmt.Sprintf("%c%02d:%02d:%02d", sign, hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, hours, minutes)
+}
+
+// strptimeFields are the components found while parsing.
+type strptimeFields struct {
+	year, century, shortYear     int
+	hasYear, hasCentury, hasYY   bool
+	month, day, yearDay          int
+	hour, minute, second, nsec   int
+	pm, hasPM                    bool
+	isoYear, isoWeek, isoWeekday int
+	unix                         *int64
+	offset                       *int
+	zone                         string
+}
+
+// Strptime parses the value according to the strftime format (see Strftime), e.g. "%d.%m.%Y %H:%M".
+//
+// Whitespace in the format matches any amount of whitespace in the value. Names of months and weekdays
+// are matched case-insensitively in their full or abbreviated form and %z accepts offsets with and without colons.
+// Missing components default to January 1 of year 0 at midnight, like time.Parse does.
+// The location is used unless the value contains an offset or a zone, %s always results in the given location.
+// As abbreviations are ambiguous, %Z only accepts "UTC", "GMT" and the abbreviations of the location
+// (e.g. "EST" and "EDT" for America/New_York).
+// %U and %W are not supported for parsing.
+func Strptime(value, format string, loc *time.Location) (time.Time, error) {
+	return english.Strptime(value, format, loc)
+}
+
+// parse parses the value with the tokens of the format.
+func (l Locale) parse(value, format string, tokens []strftimeToken, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var (
+		fields = strptimeFields{month: 1, day: 1}
+		rest   = value
+		err    error
+		// the first number which is out of range, reported after matching the rest of the value like time.Parse
+		outOfRange *mismatchError
+	)
+
+	// the offset is where the value doesn't match the format anymore
+	failed := func(offset int, err error) error {
+		if outOfRange != nil {
+			outOfRange.matched = offset
+			return fmt.Errorf("%w: '%v' doesn't match '%v': %w", ErrParseFormatted, value, format, outOfRange)
+		}
+		return fmt.Errorf("%w: '%v' doesn't match '%v': %w", ErrParseFormatted, value, format, &mismatchError{offset: offset, matched: offset, err: err})
+	}
+
+	for _, token := range tokens {
+		start := rest
+		if token.verb == 0 {
+			rest, err = matchLiteral(rest, token.literal)
+		} else {
+			rest, err = l.parseStrftimeToken(rest, token, &fields)
+		}
+		if errors.Is(err, errStrptimeRange) {
+			if outOfRange == nil {
+				outOfRange = &mismatchError{offset: len(value) - len(start), err: err}
+			}
+			continue
+		}
+		if err != nil {
+			return time.Time{}, failed(len(value)-len(start), err)
+		}
+	}
+	if rest != "" {
+		return time.Time{}, failed(len(value)-len(rest), fmt.Errorf("unexpected '%v'", rest))
+	}
+	if outOfRange != nil {
+		return time.Time{}, failed(len(value), nil)
+	}
+
+	t, err := fields.time(loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: '%v': %w", ErrParseFormatted, value, &mismatchError{offset: -1, matched: len(value), err: err})
+	}
+	return t, nil
+}
+
+// mismatchError is the reason why a value doesn't match a format at the byte offset,
+// -1 when the whole value matched but isn't a valid time, e.g. February 30.
+type mismatchError struct {
+	offset int
+	// matched is the length of the value matching the format, e.g. including a number which is out of range
+	matched int
+	err     error
+}
+
+// errStrptimeRange is returned for numbers which are out of range, e.g. 32 for %d.
+var errStrptimeRange = errors.New("out of range")
+
+func (e *mismatchError) Error() string {
+	return e.err.Error()
+}
+
+// matchLiteral removes the literal from the start of s, whitespace matches any amount of whitespace.
+func matchLiteral(s, literal string) (string, error) {
+	for _, r := range literal {
+		if unicode.IsSpace(r) {
+			s = strings.TrimLeftFunc(s, unicode.IsSpace)
+			continue
+		}
+		next, found := strings.CutPrefix(s, string(r))
+		if !found {
+			return "", fmt.Errorf("expected '%c'", r)
+		}
+		s = next
+	}
+	return s, nil
+}
+
+// parseNumber parses up to maxDigits digits (unlimited when 0) from the start of s.
+func parseNumber(s string, maxDigits int, signed bool) (int, string, error) {
+	i := 0
+	if signed && len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		i++
+	}
+	start := i
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' && (maxDigits == 0 || i-start < maxDigits) {
+		i++
+	}
+	if i == start {
+		return 0, s, errors.New("expected a number")
+	}
+	value, err := strconv.Atoi(s[:i])
+	return value, s[i:], err
+}
+
+// parseName matches the longest of the names case-insensitively and returns its index.
+// The lists contain the same names in different forms, e.g. full and abbreviated.
+func parseName(s string, lists ...[]string) (int, string, error) {
+	index, length := -1, 0
+	for _, names := range lists {
+		for i, name := range names {
+			if name != "" && len(name) > length && len(s) >= len(name) && strings.EqualFold(s[:len(name)], name) {
+				index, length = i, len(name)
+			}
+		}
+	}
+	if index < 0 {
+		return 0, s, errors.New("expected a name")
+	}
+	return index, s[length:], nil
+}
+
+// strptimeRanges are the valid values of the numeric conversions when parsing.
+var strptimeRanges = map[byte]struct{ min, max int }{
+	'd': {1, 31},
+	'e': {1, 31},
+	'g': {0, 99},
+	'H': {0, 23},
+	'I': {1, 12},
+	'j': {1, 366},
+	'k': {0, 23},
+	'l': {1, 12},
+	'm': {1, 12},
+	'M': {0, 59},
+	'S': {0, 59},
+	'u': {1, 7},
+	'V': {1, 53},
+	'w': {0, 6},
+	'y': {0, 99},
+}
+
+func (l Locale) parseStrftimeToken(s string, token strftimeToken, fields *strptimeFields) (string, error) {
+	switch token.verb {
+	case 'a', 'A':
+		// the weekday is not used to determine the date
+		_, rest, err := parseName(s, l.Weekdays[:], l.ShortWeekdays[:])
+		return rest, err
+	case 'b', 'B', 'h':
+		month, rest, err := parseName(s, l.Months[:], l.ShortMonths[:])
+		fields.month = month + 1
+		return rest, err
+	case 'p', 'P':
+		meridiem, rest, err := parseName(s, []string{l.AM, l.PM})
+		if err != nil {
+			return s, fmt.Errorf("expected %v or %v", l.AM, l.PM)
+		}
+		fields.pm, fields.hasPM = meridiem == 1, true
+		return rest, nil
+	case 'z':
+		return parseOffset(s, fields)
+	case 'Z':
+		end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 {
+			return s, errors.New("expected a time zone abbreviation")
+		}
+		fields.zone = s[:end]
+		return s[end:], nil
+	case 'N':
+		if token.literal != "" {
+			// fractional seconds of Go layouts start with their separator
+			rest, found := strings.CutPrefix(s, token.literal)
+			if !found || rest == "" || rest[0] < '0' || rest[0] > '9' {
+				if token.optional {
+					return s, nil
+				}
+				return s, fmt.Errorf("expected '%v'", token.literal)
+			}
+			s = rest
+		}
+		digits := 9
+		if token.width > 0 {
+			digits = token.width
+		}
+		start := len(s)
+		value, rest, err := parseNumber(s, digits, false)
+		if err != nil {
+			return s, err
+		}
+		for n := start - len(rest); n < 9; n++ {
+			value *= 10
+		}
+		fields.nsec = value
+		return rest, nil
+	case 's':
+		value, rest, err := parseNumber(s, 0, true)
+		if err != nil {
+			return s, err
+		}
+		unix := int64(value)
+		fields.unix = &unix
+		return rest, nil
+	}
+
+	// numbers might be padded with spaces (e.g. %e or %_d)
+	s = strings.TrimLeft(s, " ")
+
+	digits := strftimeNumbers[token.verb].width
+	if token.width > 0 {
+		digits = token.width
+	}
+	value, rest, err := parseNumber(s, digits, token.verb == 'Y' || token.verb == 'G')
+	if err != nil {
+		return s, err
+	}
+	if r, ok := strptimeRanges[token.verb]; ok && (value < r.min || value > r.max) {
+		return rest, fmt.Errorf("value %v %w for '%%%c' (%v-%v)", value, errStrptimeRange, token.verb, r.min, r.max)
+	}
+
+	switch token.verb {
+	case 'C':
+		fields.century, fields.hasCentury = value, true
+	case 'd', 'e':
+		fields.day = value
+	case 'g':
+		fields.isoYear = 2000 + value
+		if value >= 69 {
+			fields.isoYear = 1900 + value
+		}
+	case 'G':
+		fields.isoYear = value
+	case 'H', 'k', 'I', 'l':
+		fields.hour = value
+	case 'j':
+		fields.yearDay = value
+	case 'm':
+		fields.month = value
+	case 'M':
+		fields.minute = value
+	case 'S':
+		fields.second = value
+	case 'u':
+		fields.isoWeekday = value
+	case 'w':
+		fields.isoWeekday = value
+		if value == 0 {
+			fields.isoWeekday = 7
+		}
+	case 'V':
+		fields.isoWeek = value
+	case 'y':
+		fields.shortYear, fields.hasYY = value, true
+	case 'Y':
+		fields.year, fields.hasYear = value, true
+	default:
+		return s, fmt.Errorf("'%%%c' is not supported for parsing", token.verb)
+	}
+
+	return rest, nil
+}
+
+// parseOffset parses "Z", "+hh", "+hhmm", "+hh:mm" or "+hh:mm:ss".
+func parseOffset(s string, fields *strptimeFields) (string, error) {
+	if rest, found := strings.CutPrefix(s, "Z"); found {
+		offset := 0
+		fields.offset, fields.zone = &offset, "UTC"
+		return rest, nil
+	}
+	if len(s) < 3 || (s[0] != '+' && s[0] != '-') {
+		return s, errors.New("expected a UTC offset")
+	}
+
+	sign := 1
+	if s[0] == '-' {
+		sign = -1
+	}
+
+	var parts []int
+	rest := s[1:]
+	for range 3 {
+		rest = strings.TrimPrefix(rest, ":")
+		if len(rest) < 2 || rest[0] < '0' || rest[0] > '9' || rest[1] < '0' || rest[1] > '9' {
+			break
+		}
+		value, _ := strconv.Atoi(rest[:2])
+		parts = append(parts, value)
+		rest = rest[2:]
+	}
+	if len(parts) == 0 {
+		return s, errors.New("expected a UTC offset")
+	}
+	parts = append(parts, 0, 0)
+
+	offset := sign * (parts[0]*3600 + parts[1]*60 + parts[2])
+	fields.offset = &offset
+	return rest, nil
+}
+
+// time combines the parsed fields.
+func (f strptimeFields) time(loc *time.Location) (time.Time, error) {
+	if f.unix != nil {
+		return time.Unix(*f.unix, int64(f.nsec)).In(loc), nil
+	}
+
+	year := f.year
+	switch {
+	case f.hasYear:
+	case f.hasYY && f.hasCentury:
+		year = f.century*100 + f.shortYear
+	case f.hasYY:
+		// POSIX: 69-99 are in the 20th century, 00-68 in the 21st
+		year = 2000 + f.shortYear
+		if f.shortYear >= 69 {
+			year = 1900 + f.shortYear
+		}
+	case f.hasCentury:
+		year = f.century * 100
+	}
+
+	hour := f.hour
+	if f.hasPM {
+		hour %= 12
+		if f.pm {
+			hour += 12
+		}
+	}
+
+	if f.zone != "" && f.offset == nil {
+		switch {
+		case f.zone == "UTC" || f.zone == "GMT":
+			loc = time.UTC
+		default:
+			// the abbreviation has to belong to the location, e.g. "CEST" for "Europe/Berlin"
+			probe := time.Date(year, time.Month(f.month), f.day, hour, f.minute, f.second, 0, loc)
+			if name, _ := probe.Zone(); name != f.zone {
+				return time.Time{}, fmt.Errorf("unknown time zone abbreviation '%v' for %v", f.zone, loc)
+			}
+		}
+	}
+
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, f.minute, f.second, f.nsec, loc)
+	}
+
+	var t time.Time
+	switch {
+	case f.isoWeek > 0:
+		if f.isoYear == 0 {
+			f.isoYear = year
+		}
+		weekday := f.isoWeekday
+		if weekday == 0 {
+			weekday = 1
+		}
+		// January 4th is always in the first ISO week
+		jan4 := time.Date(f.isoYear, time.January, 4, 0, 0, 0, 0, time.UTC)
+		monday := 4 - (int(jan4.Weekday())+6)%7
+		year = f.isoYear
+		t = date(time.January, monday+(f.isoWeek-1)*7+weekday-1)
+	case f.yearDay > 0:
+		if last := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay(); f.yearDay > last {
+			return time.Time{}, fmt.Errorf("day %v doesn't exist in %v", f.yearDay, year)
+		}
+		t = date(time.January, f.yearDay)
+	default:
+		if f.day > daysIn(year, time.Month(f.month)) {
+			return time.Time{}, fmt.Errorf("day %v doesn't exist in %v %v", f.day, time.Month(f.month), year)
+		}
+		t = date(time.Month(f.month), f.day)
+	}
+
+	if f.offset == nil {
+		return t, nil
+	}
+	if *f.offset == 0 && f.zone == "UTC" {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC), nil
+	}
+	// like time.Parse, keep the location when it has the same offset at that time
+	if _, offset := t.Zone(); offset != *f.offset {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.FixedZone(f.zone, *f.offset))
+	}
+	return t, nil
+}
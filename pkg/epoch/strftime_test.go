@@ -0,0 +1,218 @@
+package epoch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStrftime(t *testing.T) {
+	given := time.Date(2024, 1, 5, 7, 6, 5, 123456789, time.FixedZone("CET", 3600))
+
+	testCases := []struct {
+		description string
+		format      string
+		expected    string
+		expectedErr error
+	}{
+		{description: "iso", format: "%Y-%m-%dT%H:%M:%S%z", expected: "2024-01-05T07:06:05+0100"},
+		{description: "composites", format: "%F %T %D %R", expected: "2024-01-05 07:06:05 01/05/24 07:06"},
+		{description: "names", format: "%a %A %b %B %h %Z", expected: "Fri Friday Jan January Jan CET"},
+		{description: "c locale", format: "%c", expected: "Fri Jan  5 07:06:05 2024"},
+		{description: "12 hour clock", format: "%I:%M %p %P %r", expected: "07:06 AM am 07:06:05 AM"},
+		{description: "padding", format: "%e|%k|%l|%-d|%-m|%_H|%5Y|%-j", expected: " 5| 7| 7|5|1| 7|02024|5"},
+		{description: "upper case", format: "%^a %^B", expected: "FRI JANUARY"},
+		{description: "unix timestamp", format: "%s", expected: "1704434765"},
+		{description: "nanoseconds", format: "%S.%N %S.%3N", expected: "05.123456789 05.123"},
+		{description: "day and week of year", format: "%j %U %W", expected: "005 00 01"},
+		{description: "iso week", format: "%G-W%V-%u %g", expected: "2024-W01-5 24"},
+		{description: "offsets", format: "%z %:z %::z", expected: "+0100 +01:00 +01:00:00"},
+		{description: "century and short year", format: "%C %y", expected: "20 24"},
+		{description: "literals", format: "100%% %n%t", expected: "100% \n\t"},
+		{description: "unknown conversion", format: "%Q", expectedErr: errors.New("invalid strftime format: unknown conversion '%Q'")},
+		{description: "colons without z", format: "%:d", expectedErr: errors.New("invalid strftime format: unknown conversion '%:d'")},
+		{description: "incomplete", format: "%Y-%", expectedErr: errors.New("invalid strftime format: '%Y-%' ends with an incomplete conversion")},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := Strftime(given, tt.format)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
+
+func TestStrftimeISOYear(t *testing.T) {
+	// December 30, 2024 is in the first ISO week of 2025
+	got, err := Strftime(time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), "%G-W%V-%u %Y")
+	equal(t, err, nil)
+	equal(t, got, "2025-W01-1 2024")
+}
+
+func TestStrptime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		description string
+		value       string
+		format      string
+		expected    time.Time
+		expectedErr error
+	}{
+		{
+			description: "iso with offset",
+			value:       "2024-01-05T07:06:05+0100",
+			format:      "%Y-%m-%dT%H:%M:%S%z",
+			expected:    time.Date(2024, 1, 5, 7, 6, 5, 0, berlin),
+		},
+		{
+			description: "offset with colon",
+			value:       "2024-01-05 07:06:05 -05:00",
+			format:      "%F %T %:z",
+			expected:    time.Date(2024, 1, 5, 7, 6, 5, 0, time.FixedZone("", -5*3600)),
+		},
+		{
+			description: "zulu",
+			value:       "2024-01-05T07:06:05Z",
+			format:      "%FT%T%z",
+			expected:    time.Date(2024, 1, 5, 7, 6, 5, 0, time.UTC),
+		},
+		{
+			description: "location",
+			value:       "05.07.2024 14:30",
+			format:      "%d.%m.%Y %H:%M",
+			expected:    time.Date(2024, 7, 5, 14, 30, 0, 0, berlin),
+		},
+		{
+			description: "zone abbreviation",
+			value:       "2024-07-05 14:30 CEST",
+			format:      "%F %R %Z",
+			expected:    time.Date(2024, 7, 5, 14, 30, 0, 0, berlin),
+		},
+		{
+			description: "names and 12 hour clock",
+			value:       "friday, 5 JAN 2024 7:06 pm",
+			format:      "%A, %-d %b %Y %l:%M %p",
+			expected:    time.Date(2024, 1, 5, 19, 6, 0, 0, berlin),
+		},
+		{
+			description: "space padded day",
+			value:       "Jan  5 2024",
+			format:      "%b %e %Y",
+			expected:    time.Date(2024, 1, 5, 0, 0, 0, 0, berlin),
+		},
+		{
+			description: "milliseconds",
+			value:       "07:06:05.123",
+			format:      "%T.%N",
+			expected:    time.Date(0, 1, 1, 7, 6, 5, 123000000, berlin),
+		},
+		{
+			description: "unix timestamp",
+			value:       "1704434765",
+			format:      "%s",
+			expected:    time.Unix(1704434765, 0).In(berlin),
+		},
+		{
+			description: "day of year",
+			value:       "2024-060",
+			format:      "%Y-%j",
+			expected:    time.Date(2024, 2, 29, 0, 0, 0, 0, berlin),
+		},
+		{
+			description: "iso week",
+			value:       "2025-W01-1",
+			format:      "%G-W%V-%u",
+			expected:    time.Date(2024, 12, 30, 0, 0, 0, 0, berlin),
+		},
+		{
+			description: "short year",
+			value:       "01/05/69",
+			format:      "%D",
+			expected:    time.Date(1969, 1, 5, 0, 0, 0, 0, berlin),
+		},
+		{
+			description: "non-existent day",
+			value:       "2023-02-29",
+			format:      "%F",
+			expectedErr: errors.New("failed to convert string to time: '2023-02-29': day 29 doesn't exist in February 2023"),
+		},
+		{
+			description: "out of range",
+			value:       "2024-13-01",
+			format:      "%F",
+			expectedErr: errors.New("failed to convert string to time: '2024-13-01' doesn't match '%F': value 13 out of range for '%m' (1-12)"),
+		},
+		{
+			description: "trailing text",
+			value:       "2024-01-05 07:06",
+			format:      "%F",
+			expectedErr: errors.New("failed to convert string to time: '2024-01-05 07:06' doesn't match '%F': unexpected ' 07:06'"),
+		},
+		{
+			description: "unknown abbreviation",
+			value:       "2024-07-05 EST",
+			format:      "%F %Z",
+			expectedErr: errors.New("failed to convert string to time: '2024-07-05 EST': unknown time zone abbreviation 'EST' for Europe/Berlin"),
+		},
+		{
+			description: "week of year",
+			value:       "2024 01",
+			format:      "%Y %W",
+			expectedErr: errors.New("failed to convert string to time: '2024 01' doesn't match '%Y %W': '%W' is not supported for parsing"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := Strptime(tt.value, tt.format, berlin)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			} else if tt.expectedErr != nil {
+				equalError(t, err, tt.expectedErr)
+				return
+			}
+			equal(t, got.Equal(tt.expected), true)
+			equal(t, got.Location().String(), tt.expected.Location().String())
+		})
+	}
+}
+
+func TestStrptimeZoneOfLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// abbreviations of other locations are rejected, see "unknown abbreviation" of TestStrptime
+	got, err := Strptime("2024-01-05 14:30 EST", "%F %R %Z", newYork)
+	equal(t, err, nil)
+	equal(t, got, time.Date(2024, 1, 5, 14, 30, 0, 0, newYork))
+
+	got, err = Strptime("2024-01-05 14:30 GMT", "%F %R %Z", newYork)
+	equal(t, err, nil)
+	equal(t, got, time.Date(2024, 1, 5, 14, 30, 0, 0, time.UTC))
+}
+
+func TestStrptimeRoundTrip(t *testing.T) {
+	given := time.Date(2024, 3, 9, 17, 4, 5, 6000000, time.UTC)
+	format := "%a, %-d %B %Y %I:%M:%S.%3N %p %:z"
+
+	formatted, err := Strftime(given, format)
+	equal(t, err, nil)
+
+	parsed, err := Strptime(formatted, format, time.UTC)
+	equal(t, err, nil)
+	equal(t, parsed, given)
+}
@@ -0,0 +1,99 @@
+package epoch
+
+import "time"
+
+// StartOf returns the first instant of the unit (e.g. "D" for the start of the day) containing t.
+// The boundaries are calculated in the location of t. Weeks start on Monday (ISO 8601).
+// Supported units are the same as for Calculate, an unknown unit returns the zero time.
+func StartOf(t time.Time, unit string) time.Time {
+	switch unit {
+	case "ns":
+		return t
+	case "us":
+		return t.Add(-time.Duration(t.Nanosecond() % 1000))
+	case "ms":
+		return t.Add(-time.Duration(t.Nanosecond() % (1000 * 1000)))
+	case "s":
+		return t.Add(-time.Duration(t.Nanosecond()))
+	case "m":
+		// subtract the wall clock components instead of using time.Date,
+		// otherwise, the result might jump to the other side of a DST transition.
+		return StartOf(t, "s").Add(-time.Duration(t.Second()) * time.Second)
+	case "h":
+		return StartOf(t, "m").Add(-time.Duration(t.Minute()) * time.Minute)
+	case "D":
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case "W":
+		// time.Sunday is 0, shift to make Monday the first day of the week
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+	case "M":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case "Q":
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+	case "Y":
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	}
+
+	return time.Time{}
+}
+
+// nextStart returns the first instant of the unit following the one containing t.
+func nextStart(t time.Time, unit string) time.Time {
+	start := StartOf(t, unit)
+
+	switch unit {
+	case "ns":
+		return start.Add(time.Nanosecond)
+	case "us":
+		return start.Add(time.Microsecond)
+	case "ms":
+		return start.Add(time.Millisecond)
+	case "s":
+		return start.Add(time.Second)
+	case "m":
+		return start.Add(time.Minute)
+	case "h":
+		return start.Add(time.Hour)
+	case "D":
+		return time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, t.Location())
+	case "W":
+		return time.Date(start.Year(), start.Month(), start.Day()+7, 0, 0, 0, 0, t.Location())
+	case "M":
+		return time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, t.Location())
+	case "Q":
+		return time.Date(start.Year(), start.Month()+3, 1, 0, 0, 0, 0, t.Location())
+	case "Y":
+		return time.Date(start.Year()+1, time.January, 1, 0, 0, 0, 0, t.Location())
+	}
+
+	return time.Time{}
+}
+
+// EndOf returns the last instant (with nanosecond precision) of the unit containing t,
+// e.g. "M" returns the last nanosecond of the month.
+// See StartOf for the supported units.
+func EndOf(t time.Time, unit string) time.Time {
+	next := nextStart(t, unit)
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(-time.Nanosecond)
+}
+
+// RoundTo returns the start of the unit which is closest to t.
+// Halfway values are rounded up, e.g. 12:00 is rounded to the next day.
+// See StartOf for the supported units.
+func RoundTo(t time.Time, unit string) time.Time {
+	start := StartOf(t, unit)
+	if start.IsZero() {
+		return start
+	}
+
+	next := nextStart(t, unit)
+	if t.Sub(start) < next.Sub(t) {
+		return start
+	}
+	return next
+}
@@ -0,0 +1,101 @@
+package epoch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoundaries(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	given := time.Date(2020, 8, 19, 17, 46, 45, 215239000, berlin) // Wednesday
+
+	testCases := []struct {
+		unit    string
+		startOf time.Time
+		endOf   time.Time
+		roundTo time.Time
+	}{
+		{
+			unit:    "ms",
+			startOf: time.Date(2020, 8, 19, 17, 46, 45, 215000000, berlin),
+			endOf:   time.Date(2020, 8, 19, 17, 46, 45, 215999999, berlin),
+			roundTo: time.Date(2020, 8, 19, 17, 46, 45, 215000000, berlin),
+		},
+		{
+			unit:    "s",
+			startOf: time.Date(2020, 8, 19, 17, 46, 45, 0, berlin),
+			endOf:   time.Date(2020, 8, 19, 17, 46, 45, 999999999, berlin),
+			roundTo: time.Date(2020, 8, 19, 17, 46, 45, 0, berlin),
+		},
+		{
+			unit:    "m",
+			startOf: time.Date(2020, 8, 19, 17, 46, 0, 0, berlin),
+			endOf:   time.Date(2020, 8, 19, 17, 46, 59, 999999999, berlin),
+			roundTo: time.Date(2020, 8, 19, 17, 47, 0, 0, berlin),
+		},
+		{
+			unit:    "h",
+			startOf: time.Date(2020, 8, 19, 17, 0, 0, 0, berlin),
+			endOf:   time.Date(2020, 8, 19, 17, 59, 59, 999999999, berlin),
+			roundTo: time.Date(2020, 8, 19, 18, 0, 0, 0, berlin),
+		},
+		{
+			unit:    "D",
+			startOf: time.Date(2020, 8, 19, 0, 0, 0, 0, berlin),
+			endOf:   time.Date(2020, 8, 19, 23, 59, 59, 999999999, berlin),
+			roundTo: time.Date(2020, 8, 20, 0, 0, 0, 0, berlin),
+		},
+		{
+			unit:    "W",
+			startOf: time.Date(2020, 8, 17, 0, 0, 0, 0, berlin),
+			endOf:   time.Date(2020, 8, 23, 23, 59, 59, 999999999, berlin),
+			roundTo: time.Date(2020, 8, 17, 0, 0, 0, 0, berlin),
+		},
+		{
+			unit:    "M",
+			startOf: time.Date(2020, 8, 1, 0, 0, 0, 0, berlin),
+			endOf:   time.Date(2020, 8, 31, 23, 59, 59, 999999999, berlin),
+			roundTo: time.Date(2020, 9, 1, 0, 0, 0, 0, berlin),
+		},
+		{
+			unit:    "Q",
+			startOf: time.Date(2020, 7, 1, 0, 0, 0, 0, berlin),
+			endOf:   time.Date(2020, 9, 30, 23, 59, 59, 999999999, berlin),
+			roundTo: time.Date(2020, 10, 1, 0, 0, 0, 0, berlin),
+		},
+		{
+			unit:    "Y",
+			startOf: time.Date(2020, 1, 1, 0, 0, 0, 0, berlin),
+			endOf:   time.Date(2020, 12, 31, 23, 59, 59, 999999999, berlin),
+			roundTo: time.Date(2021, 1, 1, 0, 0, 0, 0, berlin),
+		},
+		{
+			unit: "unknown",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.unit, func(t *testing.T) {
+			equal(t, StartOf(given, tt.unit), tt.startOf)
+			equal(t, EndOf(given, tt.unit), tt.endOf)
+			equal(t, RoundTo(given, tt.unit), tt.roundTo)
+		})
+	}
+}
+
+func TestBoundariesDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2020-10-25 02:30 occurs twice, this is the second occurrence (CET)
+	given := time.Date(2020, 10, 25, 1, 30, 0, 0, time.UTC).In(berlin)
+
+	equal(t, StartOf(given, "h").Format(TimeFormatGo), "2020-10-25 02:00:00 +0100 CET")
+	equal(t, EndOf(given, "D").Sub(StartOf(given, "D")), 25*time.Hour-time.Nanosecond)
+}
@@ -0,0 +1,148 @@
+package epoch
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrX509 is returned when certificates, CRLs or OCSP responses can't be read.
+var ErrX509 = errors.New("failed to read certificates")
+
+// Validity is the validity period of a certificate (NotBefore to NotAfter), a CRL (ThisUpdate to NextUpdate)
+// or the status of a certificate in an OCSP response (ThisUpdate to NextUpdate).
+type Validity struct {
+	// Subject is the common name of the certificate's subject or the CRL's issuer,
+	// the distinguished name or the first DNS name when there's no common name.
+	// It's "OCSP" and the serial number of the certificate for OCSP responses.
+	Subject string
+	Start   Field
+	// End has a zero Time for CRLs and OCSP responses without NextUpdate.
+	End Field
+	// Events are further times, the revocation times of the CRL's entries
+	// or the ProducedAt and RevocationTime of an OCSP response.
+	Events []Field
+}
+
+// Fields returns the start, end and events of the validity, named after the subject, e.g. "example.com NotAfter".
+func (v Validity) Fields() []Field {
+	fields := append([]Field{v.Start, v.End}, v.Events...)
+	for i := range fields {
+		fields[i].Name = fmt.Sprintf("%v %v", v.Subject, fields[i].Name)
+	}
+	return fields
+}
+
+// noWellDefinedExpiration is the NotAfter of certificates which don't expire (RFC 5280, section 4.1.2.5).
+var noWellDefinedExpiration = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+// ParseValidity returns the validity periods of PEM or DER encoded certificates, chains, CRLs
+// and OCSP responses in order. Other PEM blocks, e.g. private keys, are skipped.
+func ParseValidity(data []byte) ([]Validity, error) {
+	if block, _ := pem.Decode(data); block == nil {
+		return parseDERValidity(data)
+	}
+
+	var validities []Validity
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		data = rest
+
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrX509, err)
+			}
+			validities = append(validities, certificateValidity(cert))
+		case "X509 CRL":
+			crl, err := x509.ParseRevocationList(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrX509, err)
+			}
+			validities = append(validities, crlValidity(crl))
+		case "OCSP RESPONSE":
+			ocsp, err := parseOCSPValidity(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			validities = append(validities, ocsp...)
+		}
+	}
+
+	if len(validities) == 0 {
+		return nil, fmt.Errorf("%w: no certificates, CRLs or OCSP responses found", ErrX509)
+	}
+	return validities, nil
+}
+
+// parseDERValidity parses concatenated DER certificates, a single DER CRL or an OCSP response.
+func parseDERValidity(data []byte) ([]Validity, error) {
+	certs, certErr := x509.ParseCertificates(data)
+	if certErr == nil && len(certs) > 0 {
+		var validities []Validity
+		for _, cert := range certs {
+			validities = append(validities, certificateValidity(cert))
+		}
+		return validities, nil
+	}
+
+	if crl, err := x509.ParseRevocationList(data); err == nil {
+		return []Validity{crlValidity(crl)}, nil
+	}
+
+	if isOCSPResponse(data) {
+		return parseOCSPValidity(data)
+	}
+
+	// most inputs are certificates, their error is more helpful
+	if certErr == nil {
+		return nil, fmt.Errorf("%w: no certificates, CRLs or OCSP responses found", ErrX509)
+	}
+	return nil, fmt.Errorf("%w: %v", ErrX509, certErr)
+}
+
+func certificateValidity(cert *x509.Certificate) Validity {
+	subject := cert.Subject.CommonName
+	if subject == "" {
+		subject = cert.Subject.String()
+	}
+	if subject == "" && len(cert.DNSNames) > 0 {
+		subject = cert.DNSNames[0]
+	}
+
+	validity := Validity{
+		Subject: subject,
+		Start:   Field{Name: "NotBefore", Time: cert.NotBefore},
+		End:     Field{Name: "NotAfter", Time: cert.NotAfter},
+	}
+	if cert.NotAfter.Equal(noWellDefinedExpiration) {
+		validity.End.Note = "no well-defined expiration date"
+	}
+	return validity
+}
+
+func crlValidity(crl *x509.RevocationList) Validity {
+	issuer := crl.Issuer.CommonName
+	if issuer == "" {
+		issuer = crl.Issuer.String()
+	}
+
+	validity := Validity{
+		Subject: issuer,
+		Start:   Field{Name: "ThisUpdate", Time: crl.ThisUpdate},
+		End:     Field{Name: "NextUpdate", Time: crl.NextUpdate},
+	}
+	if crl.NextUpdate.IsZero() {
+		validity.End.Note = "no next update"
+	}
+	for _, entry := range crl.RevokedCertificateEntries {
+		validity.Events = append(validity.Events, Field{Name: fmt.Sprintf("revoked %X", entry.SerialNumber), Time: entry.RevocationTime})
+	}
+	return validity
+}
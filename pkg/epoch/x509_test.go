@@ -0,0 +1,225 @@
+package epoch
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// testCertificate creates a self-signed certificate in DER, which can sign CRLs.
+func testCertificate(t *testing.T, subject pkix.Name, notBefore, notAfter time.Time) ([]byte, *x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               subject,
+		DNSNames:              []string{"www.example.com"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der, cert, key
+}
+
+// testOCSP creates an unsigned OCSP response in DER with the certificate statuses.
+func testOCSP(t *testing.T, status asn1.Enumerated, producedAt time.Time, responses ...ocspSingleResponse) []byte {
+	t.Helper()
+
+	basic, err := asn1.Marshal(ocspBasicResponse{
+		ResponseData: ocspResponseData{
+			// responder by key hash
+			ResponderID: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: []byte{0x04, 0x01, 0x00}},
+			ProducedAt:  producedAt,
+			Responses:   responses,
+		},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+		Signature:          asn1.BitString{Bytes: []byte{0}, BitLength: 8},
+	})
+	equal(t, err, nil)
+
+	response := ocspResponse{Status: status}
+	if status == 0 {
+		response.Response = ocspResponseBytes{Type: oidOCSPBasic, Response: basic}
+	}
+	data, err := asn1.Marshal(response)
+	equal(t, err, nil)
+	return data
+}
+
+func TestParseValidity(t *testing.T) {
+	notBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	root, rootCert, rootKey := testCertificate(t, pkix.Name{CommonName: "Example Root"}, notBefore, noWellDefinedExpiration)
+	leaf, _, _ := testCertificate(t, pkix.Name{CommonName: "example.com"}, notBefore, notAfter)
+	unnamed, _, _ := testCertificate(t, pkix.Name{}, notBefore, notAfter)
+	organization, _, _ := testCertificate(t, pkix.Name{Organization: []string{"Example"}}, notBefore, notAfter)
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: notBefore,
+		NextUpdate: notBefore.AddDate(0, 0, 7),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: big.NewInt(0x1a2b), RevocationTime: notBefore.AddDate(0, 0, -1)},
+		},
+	}, rootCert, rootKey)
+	equal(t, err, nil)
+
+	certID := ocspCertID{
+		HashAlgorithm:  pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}},
+		IssuerNameHash: make([]byte, 20),
+		IssuerKeyHash:  make([]byte, 20),
+		SerialNumber:   big.NewInt(0x1a2b),
+	}
+	ocsp := testOCSP(t, 0, notBefore.Add(time.Hour),
+		ocspSingleResponse{CertID: certID, Good: true, ThisUpdate: notBefore, NextUpdate: notBefore.AddDate(0, 0, 7)},
+		ocspSingleResponse{CertID: certID, Revoked: ocspRevokedInfo{RevocationTime: notBefore.AddDate(0, 0, -1)}, ThisUpdate: notBefore},
+		ocspSingleResponse{CertID: certID, Unknown: true, ThisUpdate: notBefore, NextUpdate: notBefore.AddDate(0, 0, 7)},
+	)
+	producedAt := Field{Name: "ProducedAt", Time: notBefore.Add(time.Hour)}
+
+	encode := func(blockType string, blocks ...[]byte) []byte {
+		var data []byte
+		for _, block := range blocks {
+			data = append(data, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: block})...)
+		}
+		return data
+	}
+
+	leafValidity := Validity{Subject: "example.com", Start: Field{Name: "NotBefore", Time: notBefore}, End: Field{Name: "NotAfter", Time: notAfter}}
+	crlValidity := Validity{
+		Subject: "Example Root",
+		Start:   Field{Name: "ThisUpdate", Time: notBefore},
+		End:     Field{Name: "NextUpdate", Time: notBefore.AddDate(0, 0, 7)},
+		Events:  []Field{{Name: "revoked 1A2B", Time: notBefore.AddDate(0, 0, -1)}},
+	}
+	rootValidity := Validity{Subject: "Example Root", Start: Field{Name: "NotBefore", Time: notBefore}, End: Field{Name: "NotAfter", Time: noWellDefinedExpiration, Note: "no well-defined expiration date"}}
+
+	testCases := []struct {
+		description string
+		given       []byte
+		expected    []Validity
+		expectedErr error
+	}{
+		{
+			description: "pem chain",
+			given:       append(encode("CERTIFICATE", leaf, root), encode("EC PRIVATE KEY", []byte("skipped"))...),
+			expected:    []Validity{leafValidity, rootValidity},
+		},
+		{
+			description: "der chain",
+			given:       append(leaf, root...),
+			expected:    []Validity{leafValidity, rootValidity},
+		},
+		{
+			description: "subject without common name",
+			given:       encode("CERTIFICATE", organization, unnamed),
+			expected: []Validity{
+				{Subject: "O=Example", Start: Field{Name: "NotBefore", Time: notBefore}, End: Field{Name: "NotAfter", Time: notAfter}},
+				{Subject: "www.example.com", Start: Field{Name: "NotBefore", Time: notBefore}, End: Field{Name: "NotAfter", Time: notAfter}},
+			},
+		},
+		{
+			description: "pem crl",
+			given:       encode("X509 CRL", crl),
+			expected:    []Validity{crlValidity},
+		},
+		{
+			description: "der crl",
+			given:       crl,
+			expected:    []Validity{crlValidity},
+		},
+		{
+			description: "ocsp",
+			given:       ocsp,
+			expected: []Validity{
+				{Subject: "OCSP 1A2B", Start: Field{Name: "ThisUpdate", Time: notBefore}, End: Field{Name: "NextUpdate", Time: notBefore.AddDate(0, 0, 7)}, Events: []Field{producedAt}},
+				{Subject: "OCSP 1A2B", Start: Field{Name: "ThisUpdate", Time: notBefore}, End: Field{Name: "NextUpdate", Note: "no next update"}, Events: []Field{producedAt, {Name: "RevocationTime", Time: notBefore.AddDate(0, 0, -1)}}},
+				{Subject: "OCSP 1A2B", Start: Field{Name: "ThisUpdate", Time: notBefore, Note: "unknown certificate status"}, End: Field{Name: "NextUpdate", Time: notBefore.AddDate(0, 0, 7)}, Events: []Field{producedAt}},
+			},
+		},
+		{
+			description: "pem ocsp",
+			given:       encode("OCSP RESPONSE", testOCSP(t, 0, notBefore, ocspSingleResponse{CertID: certID, Good: true, ThisUpdate: notBefore})),
+			expected: []Validity{
+				{Subject: "OCSP 1A2B", Start: Field{Name: "ThisUpdate", Time: notBefore}, End: Field{Name: "NextUpdate", Note: "no next update"}, Events: []Field{{Name: "ProducedAt", Time: notBefore}}},
+			},
+		},
+		{
+			description: "unsuccessful ocsp",
+			given:       testOCSP(t, 6, notBefore),
+			expectedErr: errors.New("failed to read certificates: OCSP response status is unauthorized"),
+		},
+		{
+			description: "ocsp without statuses",
+			given:       testOCSP(t, 0, notBefore),
+			expectedErr: errors.New("failed to read certificates: OCSP response without certificate statuses"),
+		},
+		{
+			description: "pem without certificates",
+			given:       encode("EC PRIVATE KEY", []byte("skipped")),
+			expectedErr: errors.New("failed to read certificates: no certificates, CRLs or OCSP responses found"),
+		},
+		{
+			description: "invalid pem certificate",
+			given:       encode("CERTIFICATE", []byte("invalid")),
+			expectedErr: errors.New("failed to read certificates: x509: malformed certificate"),
+		},
+		{
+			description: "invalid der",
+			given:       []byte("invalid"),
+			expectedErr: errors.New("failed to read certificates: x509: malformed certificate"),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := ParseValidity(tt.given)
+			if err != nil {
+				equalError(t, err, tt.expectedErr)
+				equal(t, errors.Is(err, ErrX509), true)
+				return
+			} else if tt.expectedErr != nil {
+				t.Fatalf("expected error %q", tt.expectedErr)
+			}
+
+			for i := range got {
+				got[i].Start.Time, got[i].End.Time = got[i].Start.Time.UTC(), got[i].End.Time.UTC()
+				for j := range got[i].Events {
+					got[i].Events[j].Time = got[i].Events[j].Time.UTC()
+				}
+			}
+			equal(t, got, tt.expected)
+		})
+	}
+}
+
+func TestValidityFields(t *testing.T) {
+	validity := Validity{Subject: "example.com", Start: Field{Name: "NotBefore"}, End: Field{Name: "NotAfter", Note: "no well-defined expiration date"}}
+	equal(t, validity.Fields(), []Field{{Name: "example.com NotBefore"}, {Name: "example.com NotAfter", Note: "no well-defined expiration date"}})
+
+	validity = Validity{Subject: "Example CA", Start: Field{Name: "ThisUpdate"}, End: Field{Name: "NextUpdate"}, Events: []Field{{Name: "revoked 1A2B"}}}
+	equal(t, validity.Fields(), []Field{{Name: "Example CA ThisUpdate"}, {Name: "Example CA NextUpdate"}, {Name: "Example CA revoked 1A2B"}})
+}